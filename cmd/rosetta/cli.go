@@ -50,7 +50,7 @@ VERSION:
 
 	cliFlagObserverActualShard = cli.UintFlag{
 		Name:  "observer-actual-shard",
-		Usage: "Specifies the actual shard to observe.",
+		Usage: "Specifies the actual shard to observe (4294967295 for the metachain).",
 		Value: 0,
 	}
 
@@ -62,13 +62,13 @@ VERSION:
 
 	cliFlagObserverHttpUrl = cli.StringFlag{
 		Name:  "observer-http-url",
-		Usage: "Specifies the URL of the observer.",
+		Usage: "Specifies the URL of the observer. Multiple observers (of the same shard) can be given as a comma-separated list; requests are routed to the synced ones.",
 		Value: "http://nowhere.localhost.local",
 	}
 
 	cliFlagObserverPubKey = cli.StringFlag{
 		Name:  "observer-pubkey",
-		Usage: "Specifies the public key of the observer.",
+		Usage: "Specifies the public key (BLS, hex-encoded) of the observer - or a comma-separated list of public keys, one for each observer URL (in the same order). Each one is checked against the one reported by the observer; if left to the default (or missing), the reported one is used.",
 		Value: "0000000000000000000000000000000000000000000000000000000000000000",
 	}
 
@@ -114,11 +114,63 @@ VERSION:
 		Value: 1500,
 	}
 
+	cliFlagGasPriceModifier = cli.Float64Flag{
+		Name:  "gas-price-modifier",
+		Usage: "Specifies the gas price modifier (applied on the gas consumed for execution, when computing fees).",
+		Value: 0.01,
+	}
+
+	cliFlagNetworkConfigSnapshot = cli.StringFlag{
+		Name:  "network-config-snapshot",
+		Usage: "Specifies a snapshot file of the network config. In online mode, the network config is fetched from the observer and saved to this file (on each refresh) - when observing multiple shards, by the first observed shard only (the network config is the same for all shards). In offline mode, the network config is loaded from this file (overriding the flags above).",
+		Value: "",
+	}
+
 	cliFlagNativeCurrencySymbol = cli.StringFlag{
 		Name:  "native-currency",
 		Usage: "Specifies the symbol of the native currency (must be EGLD for mainnet, XeGLD for testnet and devnet).",
 		Value: "EGLD",
 	}
+
+	cliFlagConfigFileCustomCurrencies = cli.StringFlag{
+		Name:  "config-custom-currencies",
+		Usage: "Specifies the configuration file for custom currencies (ESDTs) - a JSON array of objects such as {\"identifier\": \"WEGLD-bd4d79\", \"symbol\": \"WEGLD\", \"decimals\": 18, \"activationNonce\": 0}.",
+		Value: "",
+	}
+
+	cliFlagMaxInlinedTransactions = cli.Uint64Flag{
+		Name:  "max-inlined-transactions",
+		Usage: "Specifies the maximum number of transactions (in a block) to be returned inline by /block. Above this threshold, only the transaction identifiers are returned (as \"other_transactions\"). 0 means no limit.",
+		Value: 0,
+	}
+
+	cliFlagGasLimitSafetyMargin = cli.Uint64Flag{
+		Name:  "gas-limit-safety-margin",
+		Usage: "Specifies the safety margin (as a percentage) to be added to the gas limit estimated by simulating a smart contract call (at /construction/metadata).",
+		Value: 10,
+	}
+
+	cliFlagTrackAllContracts = cli.BoolFlag{
+		Name:  "track-all-contracts",
+		Usage: "Specifies whether balance-changing operations of all smart contract accounts (in the observed shard) should be emitted.",
+	}
+
+	cliFlagConfigFileTrackedContracts = cli.StringFlag{
+		Name:  "config-tracked-contracts",
+		Usage: "Specifies the configuration file for the smart contract accounts whose balance-changing operations should be emitted - a JSON array of addresses.",
+		Value: "",
+	}
+
+	cliFlagHistoricalBalanceLookup = cli.BoolFlag{
+		Name:  "historical-balance-lookup",
+		Usage: "Specifies whether historical balance lookups (/account/balance at a given block) are supported. The observer(s) must keep the historical state (state pruning disabled).",
+	}
+
+	cliFlagConfigFileObservedShards = cli.StringFlag{
+		Name:  "config-observed-shards",
+		Usage: "Specifies the configuration file for observing multiple shards (each exposed as a sub-network) - a JSON array of {shard, observerUrls, observerPubkey, genesisBlock}. If set, the flags describing the observer (and its shard) are ignored.",
+		Value: "",
+	}
 )
 
 func getAllCliFlags() []cli.Flag {
@@ -138,7 +190,16 @@ func getAllCliFlags() []cli.Flag {
 		cliFlagMinGasPrice,
 		cliFlagMinGasLimit,
 		cliFlagGasPerDataByte,
+		cliFlagGasPriceModifier,
+		cliFlagNetworkConfigSnapshot,
 		cliFlagNativeCurrencySymbol,
+		cliFlagConfigFileCustomCurrencies,
+		cliFlagMaxInlinedTransactions,
+		cliFlagGasLimitSafetyMargin,
+		cliFlagTrackAllContracts,
+		cliFlagConfigFileTrackedContracts,
+		cliFlagHistoricalBalanceLookup,
+		cliFlagConfigFileObservedShards,
 	}
 }
 
@@ -159,7 +220,16 @@ type parsedCliFlags struct {
 	minGasPrice                 uint64
 	minGasLimit                 uint64
 	gasPerDataByte              uint64
+	gasPriceModifier            float64
+	networkConfigSnapshot       string
 	nativeCurrencySymbol        string
+	configFileCustomCurrencies  string
+	maxInlinedTransactions      uint64
+	gasLimitSafetyMargin        uint64
+	trackAllContracts           bool
+	configFileTrackedContracts  string
+	historicalBalanceLookup     bool
+	configFileObservedShards    string
 }
 
 func getParsedCliFlags(ctx *cli.Context) parsedCliFlags {
@@ -180,6 +250,15 @@ func getParsedCliFlags(ctx *cli.Context) parsedCliFlags {
 		minGasPrice:                 ctx.GlobalUint64(cliFlagMinGasPrice.Name),
 		minGasLimit:                 ctx.GlobalUint64(cliFlagMinGasLimit.Name),
 		gasPerDataByte:              ctx.GlobalUint64(cliFlagGasPerDataByte.Name),
+		gasPriceModifier:            ctx.GlobalFloat64(cliFlagGasPriceModifier.Name),
+		networkConfigSnapshot:       ctx.GlobalString(cliFlagNetworkConfigSnapshot.Name),
 		nativeCurrencySymbol:        ctx.GlobalString(cliFlagNativeCurrencySymbol.Name),
+		configFileCustomCurrencies:  ctx.GlobalString(cliFlagConfigFileCustomCurrencies.Name),
+		maxInlinedTransactions:      ctx.GlobalUint64(cliFlagMaxInlinedTransactions.Name),
+		gasLimitSafetyMargin:        ctx.GlobalUint64(cliFlagGasLimitSafetyMargin.Name),
+		trackAllContracts:           ctx.GlobalBool(cliFlagTrackAllContracts.Name),
+		configFileTrackedContracts:  ctx.GlobalString(cliFlagConfigFileTrackedContracts.Name),
+		historicalBalanceLookup:     ctx.GlobalBool(cliFlagHistoricalBalanceLookup.Name),
+		configFileObservedShards:    ctx.GlobalString(cliFlagConfigFileObservedShards.Name),
 	}
 }
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/ElrondNetwork/rosetta/server/resources"
+)
+
+func decodeCustomCurrenciesFile(filePath string) ([]resources.CustomCurrency, error) {
+	if len(filePath) == 0 {
+		return make([]resources.CustomCurrency, 0), nil
+	}
+
+	fileContent, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var customCurrencies []resources.CustomCurrency
+
+	err = json.Unmarshal(fileContent, &customCurrencies)
+	if err != nil {
+		return nil, err
+	}
+
+	return customCurrencies, nil
+}
+
+func decodeTrackedContractsFile(filePath string) ([]string, error) {
+	if len(filePath) == 0 {
+		return make([]string, 0), nil
+	}
+
+	fileContent, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var trackedContracts []string
+
+	err = json.Unmarshal(fileContent, &trackedContracts)
+	if err != nil {
+		return nil, err
+	}
+
+	return trackedContracts, nil
+}
+
+func decodeObservedShardsFile(filePath string) ([]resources.ObservedShard, error) {
+	if len(filePath) == 0 {
+		return make([]resources.ObservedShard, 0), nil
+	}
+
+	fileContent, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var observedShards []resources.ObservedShard
+
+	err = json.Unmarshal(fileContent, &observedShards)
+	if err != nil {
+		return nil, err
+	}
+
+	return observedShards, nil
+}
+
+// parseCommaSeparatedList splits a comma-separated list (e.g. of observer URLs); empty entries are ignored
+func parseCommaSeparatedList(list string) []string {
+	items := make([]string, 0)
+
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -10,6 +11,8 @@ import (
 
 	"github.com/ElrondNetwork/rosetta/server/factory"
 	"github.com/ElrondNetwork/rosetta/server/provider"
+	"github.com/ElrondNetwork/rosetta/server/resources"
+	"github.com/ElrondNetwork/rosetta/server/services"
 	"github.com/ElrondNetwork/rosetta/version"
 	"github.com/coinbase/rosetta-sdk-go/server"
 	"github.com/urfave/cli"
@@ -48,28 +51,103 @@ func startRosetta(ctx *cli.Context) error {
 
 	log.Info("Starting Rosetta...", "middleware", version.RosettaMiddlewareVersion, "specification", version.RosettaVersion, "node", version.NodeVersion)
 
-	networkProvider, err := provider.NewNetworkProvider(provider.ArgsNewNetworkProvider{
-		IsOffline:                   cliFlags.offline,
-		NumShards:                   cliFlags.numShards,
-		ObservedActualShard:         cliFlags.observerActualShard,
-		ObservedProjectedShard:      cliFlags.observerProjectedShard,
-		ObservedProjectedShardIsSet: cliFlags.observerProjectedShardIsSet,
-		ObserverUrl:                 cliFlags.observerHttpUrl,
-		ObserverPubkey:              cliFlags.observerPubkey,
-		ChainID:                     cliFlags.chainID,
-		GasPerDataByte:              cliFlags.gasPerDataByte,
-		MinGasPrice:                 cliFlags.minGasPrice,
-		MinGasLimit:                 cliFlags.minGasLimit,
-		NativeCurrencySymbol:        cliFlags.nativeCurrencySymbol,
-		GenesisBlockHash:            cliFlags.genesisBlock,
-	})
+	customCurrencies, err := decodeCustomCurrenciesFile(cliFlags.configFileCustomCurrencies)
 	if err != nil {
 		return err
 	}
 
-	networkProvider.LogDescription()
+	trackedContracts, err := decodeTrackedContractsFile(cliFlags.configFileTrackedContracts)
+	if err != nil {
+		return err
+	}
+
+	observedShards, err := decodeObservedShardsFile(cliFlags.configFileObservedShards)
+	if err != nil {
+		return err
+	}
+
+	isMultiShard := len(observedShards) > 0
+	if isMultiShard && cliFlags.offline {
+		return errors.New("observing multiple shards is not supported in offline mode")
+	}
+	if !isMultiShard {
+		observedShards = []resources.ObservedShard{
+			{
+				Shard:           cliFlags.observerActualShard,
+				ObserverUrls:    parseCommaSeparatedList(cliFlags.observerHttpUrl),
+				ObserverPubkeys: parseCommaSeparatedList(cliFlags.observerPubkey),
+				GenesisBlock:    cliFlags.genesisBlock,
+			},
+		}
+	}
+
+	refreshContext, stopRefresh := context.WithCancel(context.Background())
+	defer stopRefresh()
+
+	networkProviders := make([]services.NetworkProvider, 0, len(observedShards))
 
-	controllers, err := factory.CreateControllers(networkProvider)
+	for i, observedShard := range observedShards {
+		// The network config is the same for all shards (network-wide setting), thus a single snapshot is saved, by the provider of the first
+		// observed shard, avoiding concurrent writes. The snapshot can be used by an offline instance of any shard.
+		networkConfigSnapshot := ""
+		if i == 0 {
+			networkConfigSnapshot = cliFlags.networkConfigSnapshot
+		}
+
+		networkProvider, err := provider.NewNetworkProvider(provider.ArgsNewNetworkProvider{
+			IsOffline:                   cliFlags.offline,
+			NumShards:                   cliFlags.numShards,
+			ObservedActualShard:         observedShard.Shard,
+			ObservedProjectedShard:      cliFlags.observerProjectedShard,
+			ObservedProjectedShardIsSet: cliFlags.observerProjectedShardIsSet && !isMultiShard,
+			ObserverUrls:                observedShard.ObserverUrls,
+			ObserverPubkeys:             observedShard.ObserverPubkeys,
+			ChainID:                     cliFlags.chainID,
+			GasPerDataByte:              cliFlags.gasPerDataByte,
+			MinGasPrice:                 cliFlags.minGasPrice,
+			MinGasLimit:                 cliFlags.minGasLimit,
+			GasPriceModifier:            cliFlags.gasPriceModifier,
+			NetworkConfigSnapshot:       networkConfigSnapshot,
+			NativeCurrencySymbol:        cliFlags.nativeCurrencySymbol,
+			CustomCurrencies:            customCurrencies,
+			TrackAllContracts:           cliFlags.trackAllContracts,
+			TrackedContracts:            trackedContracts,
+			MaxInlinedTransactions:      cliFlags.maxInlinedTransactions,
+			GasLimitSafetyMargin:        cliFlags.gasLimitSafetyMargin,
+			HistoricalBalanceLookup:     cliFlags.historicalBalanceLookup,
+			GenesisBlockHash:            observedShard.GenesisBlock,
+		})
+		if err != nil {
+			return err
+		}
+
+		networkProvider.LogDescription()
+
+		networkProvider.StartObserversHealthChecks()
+		defer func() {
+			_ = networkProvider.Close()
+		}()
+
+		if !cliFlags.offline {
+			err = networkProvider.VerifyObserverPubkeys()
+			if err != nil {
+				return err
+			}
+
+			// If the network config cannot be fetched at startup, the one given by the flags is used (until the next refresh).
+			_ = networkProvider.RefreshNetworkConfig()
+			go networkProvider.RefreshNetworkConfigPeriodically(refreshContext)
+		}
+
+		networkProviders = append(networkProviders, networkProvider)
+	}
+
+	var controllers []server.Router
+	if isMultiShard {
+		controllers, err = factory.CreateMultiShardControllers(networkProviders)
+	} else {
+		controllers, err = factory.CreateControllers(networkProviders[0])
+	}
 	if err != nil {
 		return err
 	}
@@ -21,7 +21,7 @@ func CreateControllers(networkProvider services.NetworkProvider) ([]server.Route
 func createOfflineControllers(networkProvider services.NetworkProvider) ([]server.Router, error) {
 	log.Info("createOfflineControllers()")
 
-	asserter, err := createAsserter(networkProvider)
+	asserter, err := createAsserter(getNetworkIdentifiers(networkProvider))
 	if err != nil {
 		return nil, err
 	}
@@ -50,7 +50,7 @@ func createOfflineControllers(networkProvider services.NetworkProvider) ([]serve
 func createOnlineControllers(networkProvider services.NetworkProvider) ([]server.Router, error) {
 	log.Info("createOnlineControllers()")
 
-	asserter, err := createAsserter(networkProvider)
+	asserter, err := createAsserter(getNetworkIdentifiers(networkProvider))
 	if err != nil {
 		return nil, err
 	}
@@ -79,18 +79,44 @@ func createOnlineControllers(networkProvider services.NetworkProvider) ([]server
 	}, nil
 }
 
-func createAsserter(networkProvider services.NetworkProvider) (*asserter.Asserter, error) {
+// CreateMultiShardControllers creates the controllers of an instance that observes multiple shards (each exposed as a sub-network)
+func CreateMultiShardControllers(networkProviders []services.NetworkProvider) ([]server.Router, error) {
+	log.Info("CreateMultiShardControllers()", "numShards", len(networkProviders))
+
+	router, err := services.NewSubNetworksRouter(networkProviders)
+	if err != nil {
+		return nil, err
+	}
+
+	asserter, err := createAsserter(router.GetNetworkIdentifiers())
+	if err != nil {
+		return nil, err
+	}
+
+	return []server.Router{
+		server.NewNetworkAPIController(router, asserter),
+		server.NewAccountAPIController(router, asserter),
+		server.NewBlockAPIController(router, asserter),
+		server.NewMempoolAPIController(router, asserter),
+		server.NewConstructionAPIController(router, asserter),
+	}, nil
+}
+
+func getNetworkIdentifiers(networkProvider services.NetworkProvider) []*types.NetworkIdentifier {
+	return []*types.NetworkIdentifier{
+		{
+			Blockchain: networkProvider.GetBlockchainName(),
+			Network:    networkProvider.GetChainID(),
+		},
+	}
+}
+
+func createAsserter(networkIdentifiers []*types.NetworkIdentifier) (*asserter.Asserter, error) {
 	// The asserter automatically rejects incorrectly formatted requests.
 	asserterServer, err := asserter.NewServer(
 		services.SupportedOperationTypes,
 		false,
-		[]*types.NetworkIdentifier{
-			{
-				Blockchain: networkProvider.GetBlockchainName(),
-				Network:    networkProvider.GetChainID(),
-				// TODO: Perhaps add subnetwork identifier, as well?
-			},
-		},
+		networkIdentifiers,
 		nil,
 		false,
 		"",
@@ -0,0 +1,31 @@
+package provider
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/ElrondNetwork/rosetta/server/resources"
+)
+
+// buildUrlWithAccountQueryOptions builds the URL of an account query. The historical lookup parameters
+// ("blockNonce", "blockHash", "blockRootHash") are not (yet) supported by "common.BuildUrlWithAccountQueryOptions()", in elrond-proxy-go.
+func buildUrlWithAccountQueryOptions(path string, options resources.AccountQueryOptions) string {
+	u := url.URL{Path: path}
+	query := u.Query()
+
+	if options.OnFinalBlock {
+		query.Set(urlParameterOnFinalBlock, "true")
+	}
+	if options.BlockNonce.HasValue {
+		query.Set(urlParameterBlockNonce, strconv.FormatUint(options.BlockNonce.Value, 10))
+	}
+	if len(options.BlockHash) > 0 {
+		query.Set(urlParameterBlockHash, options.BlockHash)
+	}
+	if len(options.BlockRootHash) > 0 {
+		query.Set(urlParameterBlockRootHash, options.BlockRootHash)
+	}
+
+	u.RawQuery = query.Encode()
+	return u.String()
+}
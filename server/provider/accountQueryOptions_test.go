@@ -0,0 +1,30 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/ElrondNetwork/rosetta/server/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildUrlWithAccountQueryOptions(t *testing.T) {
+	path := "/address/erd1alice"
+
+	url := buildUrlWithAccountQueryOptions(path, resources.NewAccountQueryOptionsOnFinalBlock())
+	require.Equal(t, "/address/erd1alice?onFinalBlock=true", url)
+
+	url = buildUrlWithAccountQueryOptions(path, resources.NewAccountQueryOptionsWithBlockNonce(0))
+	require.Equal(t, "/address/erd1alice?blockNonce=0", url)
+
+	url = buildUrlWithAccountQueryOptions(path, resources.NewAccountQueryOptionsWithBlockNonce(42))
+	require.Equal(t, "/address/erd1alice?blockNonce=42", url)
+
+	url = buildUrlWithAccountQueryOptions(path, resources.NewAccountQueryOptionsWithBlockHash("abba"))
+	require.Equal(t, "/address/erd1alice?blockHash=abba", url)
+
+	url = buildUrlWithAccountQueryOptions(path, resources.AccountQueryOptions{BlockRootHash: "aabb"})
+	require.Equal(t, "/address/erd1alice?blockRootHash=aabb", url)
+
+	url = buildUrlWithAccountQueryOptions(path, resources.AccountQueryOptions{})
+	require.Equal(t, "/address/erd1alice", url)
+}
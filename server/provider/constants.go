@@ -9,3 +9,7 @@ var genesisBlockNonce = 0
 
 // Defined in the scope of the Rosetta node:
 var requestTimeoutInSeconds = 60
+var networkConfigRefreshIntervalInSeconds = 600
+var anyTokenIdentifier = "*"
+var peersCacheTTLInSeconds = 60
+var maxNumPeers = 100
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"bytes"
+
+	"github.com/ElrondNetwork/elrond-go-core/core"
+)
+
+// trackedContracts holds the smart contract accounts for which balance-changing operations are emitted.
+// By default, no smart contract account is tracked.
+type trackedContracts struct {
+	trackAll  bool
+	addresses map[string]struct{}
+}
+
+func newTrackedContracts(trackAll bool, addresses []string, pubKeyConverter core.PubkeyConverter) (*trackedContracts, error) {
+	tracked := &trackedContracts{
+		trackAll:  trackAll,
+		addresses: make(map[string]struct{}, len(addresses)),
+	}
+
+	for _, address := range addresses {
+		pubkey, err := pubKeyConverter.Decode(address)
+		if err != nil {
+			return nil, newErrBadTrackedContract(address, err.Error())
+		}
+		if !isContractPubkey(pubkey) {
+			return nil, newErrBadTrackedContract(address, "not a smart contract address")
+		}
+
+		tracked.addresses[address] = struct{}{}
+	}
+
+	return tracked, nil
+}
+
+func (tracked *trackedContracts) isTracked(address string, pubkey []byte) bool {
+	if !isContractPubkey(pubkey) {
+		return false
+	}
+	if tracked.trackAll {
+		return true
+	}
+
+	_, ok := tracked.addresses[address]
+	return ok
+}
+
+func (tracked *trackedContracts) numTracked() int {
+	return len(tracked.addresses)
+}
+
+// isContractPubkey tells whether the pubkey belongs to a smart contract (the address used as receiver by deployments is excluded)
+func isContractPubkey(pubkey []byte) bool {
+	isDeploymentAddress := bytes.Equal(pubkey, make([]byte, len(pubkey)))
+	return core.IsSmartContractAddress(pubkey) && !isDeploymentAddress
+}
@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ElrondNetwork/elrond-go-core/core/pubkeyConverter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackedContracts(t *testing.T) {
+	converter, _ := pubkeyConverter.NewBech32PubkeyConverter(pubKeyLength, log)
+
+	alice := "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th"
+	contractFoo := "erd1qqqqqqqqqqqqqpgqfejaxfh4ktp8mh8s77pl90dq0uzvh2vk396qlcwepw"
+	contractBar := "erd1qqqqqqqqqqqqqpgqqqqqqqqqqqqqqqqqzyqqqqqqqqqqqqqqqppqnzy8yl"
+	deploymentAddress := "erd1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq6gq4hu"
+
+	isTracked := func(tracked *trackedContracts, address string) bool {
+		pubkey, err := converter.Decode(address)
+		require.Nil(t, err)
+		return tracked.isTracked(address, pubkey)
+	}
+
+	t.Run("with no contracts", func(t *testing.T) {
+		tracked, err := newTrackedContracts(false, nil, converter)
+		require.Nil(t, err)
+		require.False(t, isTracked(tracked, alice))
+		require.False(t, isTracked(tracked, contractFoo))
+	})
+
+	t.Run("with all contracts", func(t *testing.T) {
+		tracked, err := newTrackedContracts(true, nil, converter)
+		require.Nil(t, err)
+		require.False(t, isTracked(tracked, alice))
+		require.True(t, isTracked(tracked, contractFoo))
+		require.True(t, isTracked(tracked, contractBar))
+		require.False(t, isTracked(tracked, deploymentAddress))
+	})
+
+	t.Run("with a list of contracts", func(t *testing.T) {
+		tracked, err := newTrackedContracts(false, []string{contractFoo}, converter)
+		require.Nil(t, err)
+		require.Equal(t, 1, tracked.numTracked())
+		require.True(t, isTracked(tracked, contractFoo))
+		require.False(t, isTracked(tracked, contractBar))
+	})
+
+	t.Run("with bad addresses", func(t *testing.T) {
+		_, err := newTrackedContracts(false, []string{alice}, converter)
+		require.True(t, errors.Is(err, errBadTrackedContract))
+
+		_, err = newTrackedContracts(false, []string{"erd1foobar"}, converter)
+		require.True(t, errors.Is(err, errBadTrackedContract))
+	})
+}
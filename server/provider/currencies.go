@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"github.com/ElrondNetwork/rosetta/server/resources"
+)
+
+// customCurrenciesRegistry holds the custom currencies (ESDTs) supported by the Rosetta node,
+// indexed both by symbol (as seen by the API consumer) and by token identifier (as seen on the Network).
+type customCurrenciesRegistry struct {
+	currencies             []resources.CustomCurrency
+	currenciesBySymbol     map[string]resources.CustomCurrency
+	currenciesByIdentifier map[string]resources.CustomCurrency
+}
+
+func newCustomCurrenciesRegistry(currencies []resources.CustomCurrency, nativeCurrencySymbol string) (*customCurrenciesRegistry, error) {
+	registry := &customCurrenciesRegistry{
+		currencies:             make([]resources.CustomCurrency, 0, len(currencies)),
+		currenciesBySymbol:     make(map[string]resources.CustomCurrency, len(currencies)),
+		currenciesByIdentifier: make(map[string]resources.CustomCurrency, len(currencies)),
+	}
+
+	for _, currency := range currencies {
+		// Symbol and identifier default to each other.
+		if len(currency.Identifier) == 0 {
+			currency.Identifier = currency.Symbol
+		}
+		if len(currency.Symbol) == 0 {
+			currency.Symbol = currency.Identifier
+		}
+
+		err := registry.checkCurrency(currency, nativeCurrencySymbol)
+		if err != nil {
+			return nil, err
+		}
+
+		registry.currencies = append(registry.currencies, currency)
+		registry.currenciesBySymbol[currency.Symbol] = currency
+		registry.currenciesByIdentifier[currency.Identifier] = currency
+	}
+
+	return registry, nil
+}
+
+func (registry *customCurrenciesRegistry) checkCurrency(currency resources.CustomCurrency, nativeCurrencySymbol string) error {
+	if len(currency.Identifier) == 0 {
+		return errMissingCustomCurrencyIdentifier
+	}
+	if currency.Symbol == nativeCurrencySymbol {
+		return newErrBadCustomCurrency(currency.Symbol, "symbol of native currency")
+	}
+	if currency.Decimals < 0 {
+		return newErrBadCustomCurrency(currency.Symbol, "negative number of decimals")
+	}
+
+	_, symbolAlreadyRegistered := registry.currenciesBySymbol[currency.Symbol]
+	if symbolAlreadyRegistered {
+		return newErrBadCustomCurrency(currency.Symbol, "duplicated symbol")
+	}
+
+	_, identifierAlreadyRegistered := registry.currenciesByIdentifier[currency.Identifier]
+	if identifierAlreadyRegistered {
+		return newErrBadCustomCurrency(currency.Symbol, "duplicated identifier")
+	}
+
+	return nil
+}
+
+func (registry *customCurrenciesRegistry) getAll() []resources.CustomCurrency {
+	return registry.currencies
+}
+
+func (registry *customCurrenciesRegistry) getBySymbol(symbol string) (resources.CustomCurrency, bool) {
+	currency, ok := registry.currenciesBySymbol[symbol]
+	return currency, ok
+}
+
+func (registry *customCurrenciesRegistry) getByIdentifier(identifier string) (resources.CustomCurrency, bool) {
+	currency, ok := registry.currenciesByIdentifier[identifier]
+	return currency, ok
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ElrondNetwork/rosetta/server/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCustomCurrenciesRegistry(t *testing.T) {
+	t.Run("with good currencies", func(t *testing.T) {
+		registry, err := newCustomCurrenciesRegistry([]resources.CustomCurrency{
+			{Identifier: "USDC-c76f1f", Symbol: "USDC", Decimals: 6, ActivationNonce: 42},
+			{Identifier: "WEGLD-bd4d79", Decimals: 18},
+			{Symbol: "ROSETTA-3a2edf", Decimals: 2},
+		}, "EGLD")
+		require.Nil(t, err)
+		require.Len(t, registry.getAll(), 3)
+
+		currency, ok := registry.getBySymbol("USDC")
+		require.True(t, ok)
+		require.Equal(t, resources.CustomCurrency{Identifier: "USDC-c76f1f", Symbol: "USDC", Decimals: 6, ActivationNonce: 42}, currency)
+
+		currency, ok = registry.getByIdentifier("WEGLD-bd4d79")
+		require.True(t, ok)
+		require.Equal(t, "WEGLD-bd4d79", currency.Symbol)
+
+		currency, ok = registry.getByIdentifier("ROSETTA-3a2edf")
+		require.True(t, ok)
+		require.Equal(t, "ROSETTA-3a2edf", currency.Symbol)
+
+		_, ok = registry.getBySymbol("USDC-c76f1f")
+		require.False(t, ok)
+	})
+
+	t.Run("with bad currencies", func(t *testing.T) {
+		_, err := newCustomCurrenciesRegistry([]resources.CustomCurrency{{Decimals: 6}}, "EGLD")
+		require.Equal(t, errMissingCustomCurrencyIdentifier, err)
+
+		_, err = newCustomCurrenciesRegistry([]resources.CustomCurrency{{Identifier: "EGLD-abcdef", Symbol: "EGLD"}}, "EGLD")
+		require.True(t, errors.Is(err, errBadCustomCurrency))
+
+		_, err = newCustomCurrenciesRegistry([]resources.CustomCurrency{{Identifier: "FOO-abcdef", Decimals: -1}}, "EGLD")
+		require.True(t, errors.Is(err, errBadCustomCurrency))
+
+		_, err = newCustomCurrenciesRegistry([]resources.CustomCurrency{{Identifier: "FOO-abcdef", Symbol: "FOO"}, {Identifier: "FOO-012345", Symbol: "FOO"}}, "EGLD")
+		require.True(t, errors.Is(err, errBadCustomCurrency))
+
+		_, err = newCustomCurrenciesRegistry([]resources.CustomCurrency{{Identifier: "FOO-abcdef", Symbol: "FOO"}, {Identifier: "FOO-abcdef", Symbol: "BAR"}}, "EGLD")
+		require.True(t, errors.Is(err, errBadCustomCurrency))
+	})
+}
@@ -9,7 +9,21 @@ import (
 var errIsOffline = errors.New("server is in offline mode")
 var errCannotGetBlock = errors.New("cannot get block")
 var errCannotGetAccount = errors.New("cannot get account")
+var errCannotGetAccountESDTBalance = errors.New("cannot get account ESDT balance")
 var errCannotGetTransaction = errors.New("cannot get transaction")
+var errCannotGetTransactionsPool = errors.New("cannot get transactions pool")
+var errCannotComputeTransactionHash = errors.New("cannot compute transaction hash")
+var errCannotGetNetworkConfig = errors.New("cannot get network config")
+var errCannotLoadNetworkConfigSnapshot = errors.New("cannot load network config snapshot")
+var errCannotSimulateTransactionCost = errors.New("cannot simulate transaction cost")
+var errCannotComputeTransactionSigningPayload = errors.New("cannot compute transaction signing payload")
+var errMissingCustomCurrencyIdentifier = errors.New("missing identifier of custom currency")
+var errBadCustomCurrency = errors.New("bad custom currency")
+var errBadTrackedContract = errors.New("bad tracked contract")
+var errCannotGetPeers = errors.New("cannot get peers")
+var errObserverPubkeyMismatch = errors.New("observer pubkey mismatch")
+var errProjectedShardOfMetachain = errors.New("projected shard cannot be set when observing the metachain")
+var errTooManyObserverPubkeys = errors.New("more observer pubkeys than observers")
 
 func newErrCannotGetBlockByNonce(nonce uint64, innerError error) error {
 	return fmt.Errorf("%w: %v, nonce = %d", errCannotGetBlock, innerError, nonce)
@@ -23,10 +37,54 @@ func newErrCannotGetAccount(address string, innerError error) error {
 	return fmt.Errorf("%w: %v, address = %s", errCannotGetAccount, innerError, address)
 }
 
+func newErrCannotGetAccountESDTBalance(address string, tokenIdentifier string, innerError error) error {
+	return fmt.Errorf("%w: %v, address = %s, token = %s", errCannotGetAccountESDTBalance, innerError, address, tokenIdentifier)
+}
+
 func newErrCannotGetTransaction(hash string, innerError error) error {
 	return fmt.Errorf("%w: %v, address = %s", errCannotGetTransaction, innerError, hash)
 }
 
+func newErrCannotComputeTransactionHash(innerError error) error {
+	return fmt.Errorf("%w: %v", errCannotComputeTransactionHash, innerError)
+}
+
+func newErrCannotComputeTransactionSigningPayload(innerError error) error {
+	return fmt.Errorf("%w: %v", errCannotComputeTransactionSigningPayload, innerError)
+}
+
+func newErrCannotGetNetworkConfig(innerError error) error {
+	return fmt.Errorf("%w: %v", errCannotGetNetworkConfig, innerError)
+}
+
+func newErrCannotLoadNetworkConfigSnapshot(file string, innerError error) error {
+	return fmt.Errorf("%w: %v, file = %s", errCannotLoadNetworkConfigSnapshot, innerError, file)
+}
+
+func newErrCannotSimulateTransactionCost(innerError error) error {
+	return fmt.Errorf("%w: %v", errCannotSimulateTransactionCost, innerError)
+}
+
+func newErrCannotGetTransactionsPool(innerError error) error {
+	return fmt.Errorf("%w: %v", errCannotGetTransactionsPool, innerError)
+}
+
+func newErrCannotGetPeers(innerError error) error {
+	return fmt.Errorf("%w: %v", errCannotGetPeers, innerError)
+}
+
+func newErrObserverPubkeyMismatch(observerUrl string, configured string, reported string) error {
+	return fmt.Errorf("%w: observer = %s, configured = %s, reported by observer = %s", errObserverPubkeyMismatch, observerUrl, configured, reported)
+}
+
+func newErrBadCustomCurrency(symbol string, reason string) error {
+	return fmt.Errorf("%w: %s, symbol = %s", errBadCustomCurrency, reason, symbol)
+}
+
+func newErrBadTrackedContract(address string, reason string) error {
+	return fmt.Errorf("%w: %s, address = %s", errBadTrackedContract, reason, address)
+}
+
 // In elrond-proxy-go, the function CallGetRestEndPoint() returns an error message as the JSON content of the erroneous HTTP response.
 // Here, we attept to decode that JSON and create an error with a "flat" error message.
 func convertStructuredApiErrToFlatErr(apiErr error) error {
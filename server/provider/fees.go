@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"math/big"
+
+	"github.com/ElrondNetwork/elrond-go-core/core"
+	"github.com/ElrondNetwork/elrond-proxy-go/data"
+	"github.com/ElrondNetwork/rosetta/server/resources"
+)
+
+func computeGasLimitForMoveBalance(tx *data.FullTransaction, networkConfig *resources.NetworkConfig) uint64 {
+	return networkConfig.MinGasLimit + networkConfig.GasPerDataByte*uint64(len(tx.Data))
+}
+
+func computeTransactionFeeForMoveBalance(tx *data.FullTransaction, networkConfig *resources.NetworkConfig) *big.Int {
+	gasLimit := computeGasLimitForMoveBalance(tx, networkConfig)
+	return core.SafeMul(gasLimit, tx.GasPrice)
+}
+
+// computeTransactionFee follows the fee computation of the protocol (the "data movement" gas at full price, the rest at the modified gas price)
+func computeTransactionFee(tx *data.FullTransaction, networkConfig *resources.NetworkConfig) *big.Int {
+	gasLimitForMoveBalance := computeGasLimitForMoveBalance(tx, networkConfig)
+	feeForMoveBalance := core.SafeMul(gasLimitForMoveBalance, tx.GasPrice)
+
+	if tx.GasLimit <= gasLimitForMoveBalance {
+		return feeForMoveBalance
+	}
+
+	gasLimitForProcessing := tx.GasLimit - gasLimitForMoveBalance
+	gasPriceForProcessing := uint64(networkConfig.GasPriceModifier * float64(tx.GasPrice))
+	feeForProcessing := core.SafeMul(gasLimitForProcessing, gasPriceForProcessing)
+
+	return big.NewInt(0).Add(feeForMoveBalance, feeForProcessing)
+}
@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/ElrondNetwork/elrond-proxy-go/data"
+	"github.com/ElrondNetwork/rosetta/server/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeTransactionFee(t *testing.T) {
+	networkConfig := &resources.NetworkConfig{
+		GasPerDataByte:   1500,
+		MinGasLimit:      50000,
+		GasPriceModifier: 0.01,
+	}
+
+	t.Run("move balance, with no data", func(t *testing.T) {
+		tx := &data.FullTransaction{GasLimit: 50000, GasPrice: 1000000000}
+		require.Equal(t, "50000000000000", computeTransactionFee(tx, networkConfig).String())
+		require.Equal(t, "50000000000000", computeTransactionFeeForMoveBalance(tx, networkConfig).String())
+	})
+
+	t.Run("move balance, with data", func(t *testing.T) {
+		tx := &data.FullTransaction{GasLimit: 56000, GasPrice: 1000000000, Data: []byte("test")}
+		require.Equal(t, "56000000000000", computeTransactionFee(tx, networkConfig).String())
+	})
+
+	t.Run("with gas limit above the one for move balance", func(t *testing.T) {
+		tx := &data.FullTransaction{GasLimit: 556000, GasPrice: 1000000000, Data: []byte("test")}
+		require.Equal(t, "61000000000000", computeTransactionFee(tx, networkConfig).String())
+		require.Equal(t, "56000000000000", computeTransactionFeeForMoveBalance(tx, networkConfig).String())
+	})
+}
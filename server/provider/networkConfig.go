@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ElrondNetwork/rosetta/server/resources"
+)
+
+// RefreshNetworkConfig fetches the network config from the observer and replaces the one held in memory
+// (initially, the one given by the CLI flags). Mismatches are logged. If a snapshot file is configured, the network config is saved to it,
+// so that an offline instance can load it.
+func (provider *networkProvider) RefreshNetworkConfig() error {
+	if provider.isOffline {
+		return errIsOffline
+	}
+
+	networkConfig, err := provider.doGetNetworkConfig()
+	if err != nil {
+		log.Warn("RefreshNetworkConfig()", "err", err)
+		return err
+	}
+
+	logNetworkConfigMismatches(provider.GetNetworkConfig(), networkConfig)
+	provider.setNetworkConfig(networkConfig)
+
+	if provider.networkConfigSnapshot != "" {
+		err = saveNetworkConfigSnapshot(provider.networkConfigSnapshot, networkConfig)
+		if err != nil {
+			log.Warn("RefreshNetworkConfig(): cannot save snapshot", "file", provider.networkConfigSnapshot, "err", err)
+		}
+	}
+
+	return nil
+}
+
+func (provider *networkProvider) doGetNetworkConfig() (*resources.NetworkConfig, error) {
+	response := &resources.NetworkConfigApiResponse{}
+
+	_, err := provider.baseProcessor.CallGetRestEndPoint(provider.getObserverUrl(), urlPathGetNetworkConfig, response)
+	if err != nil {
+		return nil, newErrCannotGetNetworkConfig(convertStructuredApiErrToFlatErr(err))
+	}
+	if response.Error != "" {
+		return nil, newErrCannotGetNetworkConfig(errors.New(response.Error))
+	}
+
+	return &response.Data.Config, nil
+}
+
+// RefreshNetworkConfigPeriodically refreshes the network config (e.g. to catch changes of gas parameters, following protocol upgrades),
+// until the context is done.
+func (provider *networkProvider) RefreshNetworkConfigPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(networkConfigRefreshIntervalInSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = provider.RefreshNetworkConfig()
+		}
+	}
+}
+
+func (provider *networkProvider) setNetworkConfig(networkConfig *resources.NetworkConfig) {
+	provider.networkConfigMutex.Lock()
+	defer provider.networkConfigMutex.Unlock()
+
+	provider.networkConfig = networkConfig
+}
+
+func logNetworkConfigMismatches(previous *resources.NetworkConfig, current *resources.NetworkConfig) {
+	for _, mismatch := range findNetworkConfigMismatches(previous, current) {
+		log.Warn("Network config mismatch (the fetched value is used)", "field", mismatch.field, "previous", mismatch.previous, "fetched", mismatch.current)
+	}
+}
+
+type networkConfigMismatch struct {
+	field    string
+	previous interface{}
+	current  interface{}
+}
+
+func findNetworkConfigMismatches(previous *resources.NetworkConfig, current *resources.NetworkConfig) []networkConfigMismatch {
+	mismatches := make([]networkConfigMismatch, 0)
+
+	addIfDifferent := func(field string, previousValue interface{}, currentValue interface{}) {
+		if previousValue != currentValue {
+			mismatches = append(mismatches, networkConfigMismatch{field: field, previous: previousValue, current: currentValue})
+		}
+	}
+
+	addIfDifferent("chainID", previous.ChainID, current.ChainID)
+	addIfDifferent("gasPerDataByte", previous.GasPerDataByte, current.GasPerDataByte)
+	addIfDifferent("minGasPrice", previous.MinGasPrice, current.MinGasPrice)
+	addIfDifferent("minGasLimit", previous.MinGasLimit, current.MinGasLimit)
+	addIfDifferent("gasPriceModifier", previous.GasPriceModifier, current.GasPriceModifier)
+
+	return mismatches
+}
+
+// The snapshot has the same format as the network config returned by the observer.
+// It's written to a temporary file, then renamed, so that readers never see a partially written snapshot.
+func saveNetworkConfigSnapshot(file string, networkConfig *resources.NetworkConfig) error {
+	content, err := json.MarshalIndent(networkConfig, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tempFile.Name())
+	}()
+
+	_, err = tempFile.Write(content)
+	if err != nil {
+		_ = tempFile.Close()
+		return err
+	}
+
+	err = tempFile.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(tempFile.Name(), 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), file)
+}
+
+func loadNetworkConfigSnapshot(file string) (*resources.NetworkConfig, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, newErrCannotLoadNetworkConfigSnapshot(file, err)
+	}
+
+	networkConfig := &resources.NetworkConfig{}
+	err = json.Unmarshal(content, networkConfig)
+	if err != nil {
+		return nil, newErrCannotLoadNetworkConfigSnapshot(file, err)
+	}
+
+	return networkConfig, nil
+}
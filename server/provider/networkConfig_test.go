@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/ElrondNetwork/rosetta/server/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkProvider_RefreshNetworkConfig(t *testing.T) {
+	isObserverDown := false
+
+	observer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isObserverDown || r.URL.Path != "/network/config" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"data":{"config":{"erd_chain_id":"D","erd_gas_per_data_byte":1500,"erd_min_gas_price":1000000000,"erd_min_gas_limit":50000,"erd_gas_price_modifier":"0.01","erd_num_shards_without_meta":3}},"error":"","code":"successful"}`))
+	}))
+	defer observer.Close()
+
+	snapshotFile := filepath.Join(t.TempDir(), "networkConfig.json")
+
+	args := createArgsNewNetworkProvider()
+	args.ObserverUrls = []string{observer.URL}
+	args.ChainID = "1"
+	args.MinGasPrice = 2000000000
+	args.NetworkConfigSnapshot = snapshotFile
+
+	provider, err := NewNetworkProvider(args)
+	require.Nil(t, err)
+	require.Equal(t, "1", provider.GetChainID())
+
+	err = provider.RefreshNetworkConfig()
+	require.Nil(t, err)
+
+	expectedNetworkConfig := &resources.NetworkConfig{
+		ChainID:          "D",
+		GasPerDataByte:   1500,
+		MinGasPrice:      1000000000,
+		MinGasLimit:      50000,
+		GasPriceModifier: 0.01,
+	}
+
+	require.Equal(t, expectedNetworkConfig, provider.GetNetworkConfig())
+	require.Equal(t, "D", provider.GetChainID())
+
+	// The snapshot can be loaded by an offline instance
+	args.IsOffline = true
+	offlineProvider, err := NewNetworkProvider(args)
+	require.Nil(t, err)
+	require.Equal(t, expectedNetworkConfig, offlineProvider.GetNetworkConfig())
+
+	err = offlineProvider.RefreshNetworkConfig()
+	require.Equal(t, errIsOffline, err)
+
+	// If the network config cannot be fetched, the previous one is kept
+	isObserverDown = true
+	err = provider.RefreshNetworkConfig()
+	require.ErrorIs(t, err, errCannotGetNetworkConfig)
+	require.Equal(t, expectedNetworkConfig, provider.GetNetworkConfig())
+}
+
+func TestNetworkProvider_LoadNetworkConfigSnapshot(t *testing.T) {
+	args := createArgsNewNetworkProvider()
+	args.IsOffline = true
+	args.NetworkConfigSnapshot = filepath.Join(t.TempDir(), "missing.json")
+
+	_, err := NewNetworkProvider(args)
+	require.ErrorIs(t, err, errCannotLoadNetworkConfigSnapshot)
+
+	args.NetworkConfigSnapshot = filepath.Join(t.TempDir(), "bad.json")
+	err = os.WriteFile(args.NetworkConfigSnapshot, []byte("{bad"), 0644)
+	require.Nil(t, err)
+
+	_, err = NewNetworkProvider(args)
+	require.ErrorIs(t, err, errCannotLoadNetworkConfigSnapshot)
+}
+
+func TestSaveNetworkConfigSnapshot(t *testing.T) {
+	directory := t.TempDir()
+	file := filepath.Join(directory, "snapshot.json")
+
+	// Concurrent writers never leave a partially written (or interleaved) snapshot behind
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := saveNetworkConfigSnapshot(file, &resources.NetworkConfig{ChainID: "T", MinGasLimit: uint64(50000 + i)})
+			require.Nil(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	networkConfig, err := loadNetworkConfigSnapshot(file)
+	require.Nil(t, err)
+	require.Equal(t, "T", networkConfig.ChainID)
+
+	// Temporary files are cleaned up
+	entries, err := os.ReadDir(directory)
+	require.Nil(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "snapshot.json", entries[0].Name())
+}
+
+func TestFindNetworkConfigMismatches(t *testing.T) {
+	previous := &resources.NetworkConfig{
+		ChainID:          "1",
+		GasPerDataByte:   1500,
+		MinGasPrice:      1000000000,
+		MinGasLimit:      50000,
+		GasPriceModifier: 0.01,
+	}
+
+	current := *previous
+	require.Len(t, findNetworkConfigMismatches(previous, &current), 0)
+
+	current.MinGasLimit = 70000
+	current.GasPriceModifier = 0.02
+	mismatches := findNetworkConfigMismatches(previous, &current)
+	require.Equal(t, []networkConfigMismatch{
+		{field: "minGasLimit", previous: uint64(50000), current: uint64(70000)},
+		{field: "gasPriceModifier", previous: 0.01, current: 0.02},
+	}, mismatches)
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sync"
+	"time"
 
 	"github.com/ElrondNetwork/elrond-go-core/core"
 	"github.com/ElrondNetwork/elrond-go-core/core/pubkeyConverter"
@@ -23,6 +25,7 @@ import (
 	"github.com/ElrondNetwork/elrond-proxy-go/process"
 	processFactory "github.com/ElrondNetwork/elrond-proxy-go/process/factory"
 	"github.com/ElrondNetwork/rosetta/server/resources"
+	"github.com/ElrondNetwork/rosetta/server/signing"
 )
 
 var (
@@ -30,8 +33,21 @@ var (
 	notApplicableFullHistoryNodesMessage = "not applicable"
 
 	urlPathGetNodeStatus      = "/node/status"
+	urlPathGetBlockByNonce    = "/block/by-nonce/%d"
+	urlPathGetBlockByHash     = "/block/by-hash/%s"
+	urlPathGetHeartbeatStatus = "/node/heartbeatstatus"
+	urlPathGetNetworkConfig   = "/network/config"
 	urlPathGetGenesisBalances = "/network/genesis-balances"
 	urlPathGetAccount         = "/address/%s"
+	urlPathGetAccountESDT     = "/address/%s/esdt/%s"
+	urlPathGetAccountAllESDTs = "/address/%s/esdt"
+	urlPathGetTransactionPool = "/transaction/pool?fields=hash,sender,receiver,value,data,gaslimit,gasprice"
+	urlPathSimulateTxCost     = "/transaction/cost"
+
+	urlParameterOnFinalBlock  = "onFinalBlock"
+	urlParameterBlockNonce    = "blockNonce"
+	urlParameterBlockHash     = "blockHash"
+	urlParameterBlockRootHash = "blockRootHash"
 )
 
 var log = logger.GetOrCreate("server/provider")
@@ -42,13 +58,21 @@ type ArgsNewNetworkProvider struct {
 	ObservedActualShard         uint32
 	ObservedProjectedShard      uint32
 	ObservedProjectedShardIsSet bool
-	ObserverUrl                 string
-	ObserverPubkey              string
+	ObserverUrls                []string
+	ObserverPubkeys             []string
 	ChainID                     string
 	GasPerDataByte              uint64
 	MinGasPrice                 uint64
 	MinGasLimit                 uint64
+	GasPriceModifier            float64
+	NetworkConfigSnapshot       string
 	NativeCurrencySymbol        string
+	CustomCurrencies            []resources.CustomCurrency
+	TrackAllContracts           bool
+	TrackedContracts            []string
+	MaxInlinedTransactions      uint64
+	GasLimitSafetyMargin        uint64
+	HistoricalBalanceLookup     bool
 	GenesisBlockHash            string
 	GenesisTimestamp            int64
 }
@@ -60,25 +84,53 @@ type networkProvider struct {
 	baseProcessor        process.Processor
 	accountProcessor     facade.AccountProcessor
 	transactionProcessor facade.TransactionProcessor
-	blockProcessor       facade.BlockProcessor
+	syncStateChecker     nodesSyncStateChecker
 
-	hasher                hashing.Hasher
-	marshalizerForHashing marshal.Marshalizer
+	hasher                 hashing.Hasher
+	marshalizerForHashing  marshal.Marshalizer
+	signingPayloadComputer *signing.TransactionSigningPayloadComputer
 
 	observedActualShard         uint32
 	observedProjectedShard      uint32
 	observedProjectedShardIsSet bool
-	observerUrl                 string
-	observerPubkey              string
+	observerUrls                []string
 	nativeCurrencySymbol        string
+	customCurrencies            *customCurrenciesRegistry
+	trackedContracts            *trackedContracts
+	maxInlinedTransactions      uint64
+	gasLimitSafetyMargin        uint64
+	historicalBalanceLookup     bool
 	genesisBlockHash            string
 	genesisTimestamp            int64
 
-	networkConfig *resources.NetworkConfig
+	networkConfig         *resources.NetworkConfig
+	networkConfigMutex    sync.RWMutex
+	networkConfigSnapshot string
+
+	// observerPubkeys holds the pubkey of each observer, keyed by URL
+	observerPubkeys      map[string]string
+	observerPubkeysMutex sync.RWMutex
+
+	peers          []data.PubKeyHeartbeat
+	peersTimestamp time.Time
+	peersMutex     sync.Mutex
+}
+
+type nodesSyncStateChecker interface {
+	StartNodesSyncStateChecks()
+	Close() error
 }
 
 // TODO: Move constructor calls to /factory. Receive dependencies in constructor.
 func NewNetworkProvider(args ArgsNewNetworkProvider) (*networkProvider, error) {
+	if args.ObservedActualShard == core.MetachainShardId && args.ObservedProjectedShardIsSet {
+		return nil, errProjectedShardOfMetachain
+	}
+
+	if len(args.ObserverPubkeys) > len(args.ObserverUrls) {
+		return nil, errTooManyObserverPubkeys
+	}
+
 	shardCoordinator, err := sharding.NewMultiShardCoordinator(args.NumShards, args.ObservedActualShard)
 	if err != nil {
 		return nil, err
@@ -89,12 +141,21 @@ func NewNetworkProvider(args ArgsNewNetworkProvider) (*networkProvider, error) {
 		return nil, err
 	}
 
-	observers := []*data.NodeData{
-		{
+	// All the observers belong to the observed shard. Initially, they are all considered synced (until the first health check).
+	// The pubkeys are given in the same order as the observers; missing ones are learned from the observers (see VerifyObserverPubkeys()).
+	observers := make([]*data.NodeData, 0, len(args.ObserverUrls))
+	observerPubkeys := make(map[string]string, len(args.ObserverUrls))
+	for i, observerUrl := range args.ObserverUrls {
+		observers = append(observers, &data.NodeData{
 			ShardId:  args.ObservedActualShard,
-			Address:  args.ObserverUrl,
+			Address:  observerUrl,
 			IsSynced: true,
-		},
+		})
+
+		observerPubkeys[observerUrl] = ""
+		if i < len(args.ObserverPubkeys) {
+			observerPubkeys[observerUrl] = args.ObserverPubkeys[i]
+		}
 	}
 
 	observersProvider, err := observer.NewSimpleNodesProvider(observers, notApplicableConfigurationFilePath)
@@ -132,6 +193,11 @@ func NewNetworkProvider(args ArgsNewNetworkProvider) (*networkProvider, error) {
 		return nil, err
 	}
 
+	signingPayloadComputer, err := signing.NewTransactionSigningPayloadComputer(pubKeyConverter)
+	if err != nil {
+		return nil, err
+	}
+
 	marshalizerForHashing, err := marshalFactory.NewMarshalizer(marshalizerForHashingType)
 	if err != nil {
 		return nil, err
@@ -147,11 +213,36 @@ func NewNetworkProvider(args ArgsNewNetworkProvider) (*networkProvider, error) {
 		return nil, err
 	}
 
-	blockProcessor, err := process.NewBlockProcessor(&disabledExternalStorageConnector{}, baseProcessor)
+	customCurrencies, err := newCustomCurrenciesRegistry(args.CustomCurrencies, args.NativeCurrencySymbol)
+	if err != nil {
+		return nil, err
+	}
+
+	trackedContracts, err := newTrackedContracts(args.TrackAllContracts, args.TrackedContracts, pubKeyConverter)
 	if err != nil {
 		return nil, err
 	}
 
+	networkConfig := &resources.NetworkConfig{
+		ChainID:          args.ChainID,
+		GasPerDataByte:   args.GasPerDataByte,
+		MinGasPrice:      args.MinGasPrice,
+		MinGasLimit:      args.MinGasLimit,
+		GasPriceModifier: args.GasPriceModifier,
+	}
+
+	// In offline mode, the network config can be loaded from a snapshot (saved by an online instance).
+	// In online mode, the network config is fetched from the observer (see RefreshNetworkConfig()).
+	if args.IsOffline && args.NetworkConfigSnapshot != "" {
+		networkConfigOfSnapshot, err := loadNetworkConfigSnapshot(args.NetworkConfigSnapshot)
+		if err != nil {
+			return nil, err
+		}
+
+		logNetworkConfigMismatches(networkConfig, networkConfigOfSnapshot)
+		networkConfig = networkConfigOfSnapshot
+	}
+
 	return &networkProvider{
 		isOffline: args.IsOffline,
 
@@ -159,26 +250,28 @@ func NewNetworkProvider(args ArgsNewNetworkProvider) (*networkProvider, error) {
 		baseProcessor:        baseProcessor,
 		accountProcessor:     accountProcessor,
 		transactionProcessor: transactionProcessor,
-		blockProcessor:       blockProcessor,
+		syncStateChecker:     baseProcessor,
 
-		hasher:                hasher,
-		marshalizerForHashing: marshalizerForHashing,
+		hasher:                 hasher,
+		marshalizerForHashing:  marshalizerForHashing,
+		signingPayloadComputer: signingPayloadComputer,
 
 		observedActualShard:         args.ObservedActualShard,
 		observedProjectedShard:      args.ObservedProjectedShard,
 		observedProjectedShardIsSet: args.ObservedProjectedShardIsSet,
-		observerUrl:                 args.ObserverUrl,
-		observerPubkey:              args.ObserverPubkey,
+		observerUrls:                args.ObserverUrls,
 		nativeCurrencySymbol:        args.NativeCurrencySymbol,
+		customCurrencies:            customCurrencies,
+		trackedContracts:            trackedContracts,
+		maxInlinedTransactions:      args.MaxInlinedTransactions,
+		gasLimitSafetyMargin:        args.GasLimitSafetyMargin,
+		historicalBalanceLookup:     args.HistoricalBalanceLookup,
 		genesisBlockHash:            args.GenesisBlockHash,
+		observerPubkeys:             observerPubkeys,
 		genesisTimestamp:            args.GenesisTimestamp,
 
-		networkConfig: &resources.NetworkConfig{
-			ChainID:        args.ChainID,
-			GasPerDataByte: args.GasPerDataByte,
-			MinGasPrice:    args.MinGasPrice,
-			MinGasLimit:    args.MinGasLimit,
-		},
+		networkConfig:         networkConfig,
+		networkConfigSnapshot: args.NetworkConfigSnapshot,
 	}, nil
 }
 
@@ -194,7 +287,7 @@ func (provider *networkProvider) GetBlockchainName() string {
 
 // GetChainID gets the chain identifier ("1" for mainnet, "D" for devnet etc.)
 func (provider *networkProvider) GetChainID() string {
-	return provider.networkConfig.ChainID
+	return provider.GetNetworkConfig().ChainID
 }
 
 // GetNativeCurrency gets the native currency (EGLD, 18 decimals)
@@ -205,13 +298,42 @@ func (provider *networkProvider) GetNativeCurrency() resources.NativeCurrency {
 	}
 }
 
-// GetObserverPubkey gets the pubkey of the connected observer
-func (provider *networkProvider) GetObserverPubkey() string {
-	return provider.observerPubkey
+// GetCustomCurrencies gets the custom currencies (ESDTs) supported by the Rosetta node (as configured)
+func (provider *networkProvider) GetCustomCurrencies() []resources.CustomCurrency {
+	return provider.customCurrencies.getAll()
 }
 
-// GetNetworkConfig gets the network config (once fetched, the network config is indefinitely held in memory)
+// GetCustomCurrencyBySymbol gets a supported custom currency (ESDT) by symbol
+func (provider *networkProvider) GetCustomCurrencyBySymbol(symbol string) (resources.CustomCurrency, bool) {
+	return provider.customCurrencies.getBySymbol(symbol)
+}
+
+// GetCustomCurrencyByIdentifier gets a supported custom currency (ESDT) by token identifier
+func (provider *networkProvider) GetCustomCurrencyByIdentifier(identifier string) (resources.CustomCurrency, bool) {
+	return provider.customCurrencies.getByIdentifier(identifier)
+}
+
+// GetGasLimitSafetyMargin gets the safety margin (as a percentage) to be added to the gas limit estimated by simulating a smart contract call
+func (provider *networkProvider) GetGasLimitSafetyMargin() uint64 {
+	return provider.gasLimitSafetyMargin
+}
+
+// GetMaxInlinedTransactions gets the maximum number of transactions (in a block) to be returned inline, by /block (0 means no limit)
+func (provider *networkProvider) GetMaxInlinedTransactions() uint64 {
+	return provider.maxInlinedTransactions
+}
+
+// IsHistoricalBalanceLookupEnabled returns whether historical balance lookups are supported (i.e. the observers keep the historical state)
+func (provider *networkProvider) IsHistoricalBalanceLookupEnabled() bool {
+	return provider.historicalBalanceLookup
+}
+
+// GetNetworkConfig gets the network config (held in memory, periodically refreshed in online mode).
+// The returned object must not be altered.
 func (provider *networkProvider) GetNetworkConfig() *resources.NetworkConfig {
+	provider.networkConfigMutex.RLock()
+	defer provider.networkConfigMutex.RUnlock()
+
 	return provider.networkConfig
 }
 
@@ -236,7 +358,7 @@ func (provider *networkProvider) GetGenesisBalances() ([]*resources.GenesisBalan
 
 	response := &resources.GenesisBalancesApiResponse{}
 
-	_, err := provider.baseProcessor.CallGetRestEndPoint(provider.observerUrl, urlPathGetGenesisBalances, &response)
+	_, err := provider.baseProcessor.CallGetRestEndPoint(provider.getObserverUrl(), urlPathGetGenesisBalances, &response)
 	if err != nil {
 		return nil, convertStructuredApiErrToFlatErr(err)
 	}
@@ -249,57 +371,86 @@ func (provider *networkProvider) GetGenesisBalances() ([]*resources.GenesisBalan
 
 // GetLatestBlockSummary gets a summary of the latest block
 func (provider *networkProvider) GetLatestBlockSummary() (*resources.BlockSummary, error) {
+	_, latestBlockSummary, err := provider.GetNodeStatusAndLatestBlockSummary()
+	return latestBlockSummary, err
+}
+
+// GetNodeStatusAndLatestBlockSummary gets the status of the observer (e.g. whether it is syncing), along with a summary of the latest block.
+// The latest block is derived from the very same node status (thus, the two are consistent with each other).
+func (provider *networkProvider) GetNodeStatusAndLatestBlockSummary() (*resources.NodeStatus, *resources.BlockSummary, error) {
 	if provider.isOffline {
-		return nil, errIsOffline
+		return nil, nil, errIsOffline
 	}
 
-	latestBlockNonce, err := provider.getLatestBlockNonce()
+	// The node status and the block itself are fetched from the same observer.
+	observerUrl := provider.getObserverUrl()
+
+	nodeStatus, err := provider.getNodeStatus(observerUrl)
 	if err != nil {
-		return nil, err
+		log.Warn("GetNodeStatusAndLatestBlockSummary()", "observer", observerUrl, "err", err)
+		return nil, nil, err
 	}
 
-	log.Debug("GetLatestBlockSummary()", "latestBlockNonce", latestBlockNonce)
+	// The pubkey always describes the observer that served the request (even if it does not report it)
+	if nodeStatus.PublicKeyBlockSign == "" {
+		nodeStatus.PublicKeyBlockSign = provider.getObserverPubkey(observerUrl)
+	}
+
+	log.Trace("GetNodeStatusAndLatestBlockSummary()",
+		"nonce", nodeStatus.HighestNonce,
+		"probableHighestNonce", nodeStatus.ProbableHighestNonce,
+		"isSyncing", nodeStatus.IsSyncing,
+	)
+
+	latestBlockNonce := getLatestBlockNonceOfNodeStatus(nodeStatus)
+
+	log.Debug("GetNodeStatusAndLatestBlockSummary()", "latestBlockNonce", latestBlockNonce, "observer", observerUrl)
 
 	queryOptions := common.BlockQueryOptions{
 		WithTransactions: false,
 		WithLogs:         false,
 	}
 
-	blockResponse, err := provider.blockProcessor.GetBlockByNonce(
-		provider.observedActualShard,
-		latestBlockNonce,
-		queryOptions,
-	)
+	blockResponse := &data.BlockApiResponse{}
+	path := common.BuildUrlWithBlockQueryOptions(fmt.Sprintf(urlPathGetBlockByNonce, latestBlockNonce), queryOptions)
+
+	_, err = provider.baseProcessor.CallGetRestEndPoint(observerUrl, path, blockResponse)
 	if err != nil {
-		return nil, newErrCannotGetBlockByNonce(latestBlockNonce, err)
+		return nil, nil, newErrCannotGetBlockByNonce(latestBlockNonce, err)
 	}
 
-	return &resources.BlockSummary{
+	latestBlockSummary := &resources.BlockSummary{
 		Nonce:             blockResponse.Data.Block.Nonce,
 		Hash:              blockResponse.Data.Block.Hash,
 		PreviousBlockHash: blockResponse.Data.Block.PrevBlockHash,
 		Timestamp:         int64(blockResponse.Data.Block.Timestamp),
-	}, nil
+	}
+
+	return nodeStatus, latestBlockSummary, nil
 }
 
-func (provider *networkProvider) getLatestBlockNonce() (uint64, error) {
-	nodeStatus, err := provider.getNodeStatus()
+func (provider *networkProvider) getLatestBlockNonce(observerUrl string) (uint64, error) {
+	nodeStatus, err := provider.getNodeStatus(observerUrl)
 	if err != nil {
 		return 0, err
 	}
 
+	return getLatestBlockNonceOfNodeStatus(nodeStatus), nil
+}
+
+func getLatestBlockNonceOfNodeStatus(nodeStatus *resources.NodeStatus) uint64 {
 	// In the context of scheduled transactions, make sure the N+1 block is final, as well.
-	return nodeStatus.HighestFinalNonce - 1, nil
+	return nodeStatus.HighestFinalNonce - 1
 }
 
-func (provider *networkProvider) getNodeStatus() (*resources.NodeStatus, error) {
+func (provider *networkProvider) getNodeStatus(observerUrl string) (*resources.NodeStatus, error) {
 	if provider.isOffline {
 		return nil, errIsOffline
 	}
 
 	response := &resources.NodeStatusApiResponse{}
 
-	_, err := provider.baseProcessor.CallGetRestEndPoint(provider.observerUrl, urlPathGetNodeStatus, &response)
+	_, err := provider.baseProcessor.CallGetRestEndPoint(observerUrl, urlPathGetNodeStatus, &response)
 	if err != nil {
 		return nil, convertStructuredApiErrToFlatErr(err)
 	}
@@ -316,7 +467,10 @@ func (provider *networkProvider) GetBlockByNonce(nonce uint64) (*data.Block, err
 		return nil, errIsOffline
 	}
 
-	latestNonce, err := provider.getLatestBlockNonce()
+	// All the queries (including the ones for the neighbouring blocks, see simplifyBlockWithScheduledTransactions()) go to the same observer.
+	observerUrl := provider.getObserverUrl()
+
+	latestNonce, err := provider.getLatestBlockNonce(observerUrl)
 	if err != nil {
 		return nil, err
 	}
@@ -325,13 +479,13 @@ func (provider *networkProvider) GetBlockByNonce(nonce uint64) (*data.Block, err
 		return nil, errCannotGetBlock
 	}
 
-	block, err := provider.doGetBlockByNonce(nonce)
+	block, err := provider.doGetBlockByNonce(observerUrl, nonce)
 	if err != nil {
-		log.Warn("GetBlockByNonce()", "nonce", nonce, "err", err)
+		log.Warn("GetBlockByNonce()", "nonce", nonce, "observer", observerUrl, "err", err)
 		return nil, err
 	}
 
-	err = provider.simplifyBlockWithScheduledTransactions(block)
+	err = provider.simplifyBlockWithScheduledTransactions(observerUrl, block)
 	if err != nil {
 		return nil, err
 	}
@@ -339,13 +493,16 @@ func (provider *networkProvider) GetBlockByNonce(nonce uint64) (*data.Block, err
 	return block, nil
 }
 
-func (provider *networkProvider) doGetBlockByNonce(nonce uint64) (*data.Block, error) {
+func (provider *networkProvider) doGetBlockByNonce(observerUrl string, nonce uint64) (*data.Block, error) {
 	queryOptions := common.BlockQueryOptions{
 		WithTransactions: true,
 		WithLogs:         true,
 	}
 
-	response, err := provider.blockProcessor.GetBlockByNonce(provider.observedActualShard, nonce, queryOptions)
+	response := &data.BlockApiResponse{}
+	path := common.BuildUrlWithBlockQueryOptions(fmt.Sprintf(urlPathGetBlockByNonce, nonce), queryOptions)
+
+	_, err := provider.baseProcessor.CallGetRestEndPoint(observerUrl, path, response)
 	if err != nil {
 		return nil, newErrCannotGetBlockByNonce(nonce, convertStructuredApiErrToFlatErr(err))
 	}
@@ -362,13 +519,16 @@ func (provider *networkProvider) GetBlockByHash(hash string) (*data.Block, error
 		return nil, errIsOffline
 	}
 
-	block, err := provider.doGetBlockByHash(hash)
+	// All the queries (including the ones for the neighbouring blocks, see simplifyBlockWithScheduledTransactions()) go to the same observer.
+	observerUrl := provider.getObserverUrl()
+
+	block, err := provider.doGetBlockByHash(observerUrl, hash)
 	if err != nil {
-		log.Warn("GetBlockByHash()", "hash", hash, "err", err)
+		log.Warn("GetBlockByHash()", "hash", hash, "observer", observerUrl, "err", err)
 		return nil, err
 	}
 
-	err = provider.simplifyBlockWithScheduledTransactions(block)
+	err = provider.simplifyBlockWithScheduledTransactions(observerUrl, block)
 	if err != nil {
 		return nil, err
 	}
@@ -376,13 +536,16 @@ func (provider *networkProvider) GetBlockByHash(hash string) (*data.Block, error
 	return block, nil
 }
 
-func (provider *networkProvider) doGetBlockByHash(hash string) (*data.Block, error) {
+func (provider *networkProvider) doGetBlockByHash(observerUrl string, hash string) (*data.Block, error) {
 	queryOptions := common.BlockQueryOptions{
 		WithTransactions: true,
 		WithLogs:         true,
 	}
 
-	response, err := provider.blockProcessor.GetBlockByHash(provider.observedActualShard, hash, queryOptions)
+	response := &data.BlockApiResponse{}
+	path := common.BuildUrlWithBlockQueryOptions(fmt.Sprintf(urlPathGetBlockByHash, hash), queryOptions)
+
+	_, err := provider.baseProcessor.CallGetRestEndPoint(observerUrl, path, response)
 	if err != nil {
 		return nil, newErrCannotGetBlockByHash(hash, convertStructuredApiErrToFlatErr(err))
 	}
@@ -393,13 +556,13 @@ func (provider *networkProvider) doGetBlockByHash(hash string) (*data.Block, err
 	return &response.Data.Block, nil
 }
 
-// GetAccount gets an account by address
-func (provider *networkProvider) GetAccount(address string) (*data.AccountModel, error) {
+// GetAccount gets an account by address, at the block specified by the query options
+func (provider *networkProvider) GetAccount(address string, options resources.AccountQueryOptions) (*data.AccountModel, error) {
 	if provider.isOffline {
 		return nil, errIsOffline
 	}
 
-	account, err := provider.doGetAccount(address)
+	account, err := provider.doGetAccount(address, options)
 	if err != nil {
 		log.Warn("GetAccount()", "address", address, "err", err)
 		return nil, err
@@ -416,12 +579,11 @@ func (provider *networkProvider) GetAccount(address string) (*data.AccountModel,
 	return account, nil
 }
 
-func (provider *networkProvider) doGetAccount(address string) (*data.AccountModel, error) {
-	options := common.AccountQueryOptions{OnFinalBlock: true}
-	url := common.BuildUrlWithAccountQueryOptions(fmt.Sprintf(urlPathGetAccount, address), options)
+func (provider *networkProvider) doGetAccount(address string, options resources.AccountQueryOptions) (*data.AccountModel, error) {
+	url := buildUrlWithAccountQueryOptions(fmt.Sprintf(urlPathGetAccount, address), options)
 	response := &data.AccountApiResponse{}
 
-	_, err := provider.baseProcessor.CallGetRestEndPoint(provider.observerUrl, url, &response)
+	_, err := provider.baseProcessor.CallGetRestEndPoint(provider.getObserverUrl(), url, &response)
 	if err != nil {
 		return nil, newErrCannotGetAccount(address, convertStructuredApiErrToFlatErr(err))
 	}
@@ -432,6 +594,119 @@ func (provider *networkProvider) doGetAccount(address string) (*data.AccountMode
 	return &response.Data, nil
 }
 
+// GetAccountESDTBalance gets the balance of a given ESDT token, for an account (at the block specified by the query options)
+func (provider *networkProvider) GetAccountESDTBalance(
+	address string,
+	tokenIdentifier string,
+	options resources.AccountQueryOptions,
+) (*resources.AccountESDTBalance, error) {
+	if provider.isOffline {
+		return nil, errIsOffline
+	}
+
+	balance, err := provider.doGetAccountESDTBalance(address, tokenIdentifier, options)
+	if err != nil {
+		log.Warn("GetAccountESDTBalance()", "address", address, "token", tokenIdentifier, "err", err)
+		return nil, err
+	}
+
+	log.Trace("GetAccountESDTBalance()",
+		"address", address,
+		"token", tokenIdentifier,
+		"balance", balance.Balance,
+		"block", balance.BlockInfo.Nonce,
+		"blockHash", balance.BlockInfo.Hash,
+	)
+
+	return balance, nil
+}
+
+func (provider *networkProvider) doGetAccountESDTBalance(
+	address string,
+	tokenIdentifier string,
+	options resources.AccountQueryOptions,
+) (*resources.AccountESDTBalance, error) {
+	url := buildUrlWithAccountQueryOptions(fmt.Sprintf(urlPathGetAccountESDT, address, tokenIdentifier), options)
+	response := &resources.AccountESDTBalanceApiResponse{}
+
+	_, err := provider.baseProcessor.CallGetRestEndPoint(provider.getObserverUrl(), url, &response)
+	if err != nil {
+		return nil, newErrCannotGetAccountESDTBalance(address, tokenIdentifier, convertStructuredApiErrToFlatErr(err))
+	}
+	if response.Error != "" {
+		return nil, newErrCannotGetAccountESDTBalance(address, tokenIdentifier, errors.New(response.Error))
+	}
+
+	return &resources.AccountESDTBalance{
+		Balance:   response.Data.TokenData.Balance,
+		BlockInfo: response.Data.BlockInfo,
+	}, nil
+}
+
+// GetAccountAllESDTBalances gets the balances of all ESDT tokens held by an account (indexed by token identifier),
+// at the block specified by the query options
+func (provider *networkProvider) GetAccountAllESDTBalances(
+	address string,
+	options resources.AccountQueryOptions,
+) (map[string]*resources.AccountESDTBalance, error) {
+	if provider.isOffline {
+		return nil, errIsOffline
+	}
+
+	balances, err := provider.doGetAccountAllESDTBalances(address, options)
+	if err != nil {
+		log.Warn("GetAccountAllESDTBalances()", "address", address, "err", err)
+		return nil, err
+	}
+
+	log.Trace("GetAccountAllESDTBalances()", "address", address, "numTokens", len(balances))
+
+	return balances, nil
+}
+
+func (provider *networkProvider) doGetAccountAllESDTBalances(
+	address string,
+	options resources.AccountQueryOptions,
+) (map[string]*resources.AccountESDTBalance, error) {
+	url := buildUrlWithAccountQueryOptions(fmt.Sprintf(urlPathGetAccountAllESDTs, address), options)
+	response := &resources.AccountAllESDTBalancesApiResponse{}
+
+	_, err := provider.baseProcessor.CallGetRestEndPoint(provider.getObserverUrl(), url, &response)
+	if err != nil {
+		return nil, newErrCannotGetAccountESDTBalance(address, anyTokenIdentifier, convertStructuredApiErrToFlatErr(err))
+	}
+	if response.Error != "" {
+		return nil, newErrCannotGetAccountESDTBalance(address, anyTokenIdentifier, errors.New(response.Error))
+	}
+
+	balances := make(map[string]*resources.AccountESDTBalance, len(response.Data.Tokens))
+
+	// Keys are token identifiers (for fungible tokens) or token identifiers suffixed by the nonce (for the others).
+	for tokenIdentifier, tokenData := range response.Data.Tokens {
+		balances[tokenIdentifier] = &resources.AccountESDTBalance{
+			Balance:   tokenData.Balance,
+			BlockInfo: response.Data.BlockInfo,
+		}
+	}
+
+	return balances, nil
+}
+
+// GetObservedActualShard gets the shard observed by this instance of the network provider
+func (provider *networkProvider) GetObservedActualShard() uint32 {
+	return provider.observedActualShard
+}
+
+// ComputeShardIdOfAddress computes the (actual) shard of an address
+func (provider *networkProvider) ComputeShardIdOfAddress(address string) (uint32, error) {
+	pubKey, err := provider.ConvertAddressToPubKey(address)
+	if err != nil {
+		return 0, err
+	}
+
+	return provider.baseProcessor.ComputeShardId(pubKey)
+}
+
 // IsAddressObserved returns whether the address is observed (i.e. is located in an observed shard)
 func (provider *networkProvider) IsAddressObserved(address string) (bool, error) {
 	pubKey, err := provider.ConvertAddressToPubKey(address)
@@ -444,6 +719,11 @@ func (provider *networkProvider) IsAddressObserved(address string) (bool, error)
 		return false, err
 	}
 
+	// The metachain only holds (system) smart contracts, which aren't held by any other shard - projected shards do not apply.
+	if shard == core.MetachainShardId || provider.isObservingMetachain() {
+		return shard == provider.observedActualShard, nil
+	}
+
 	isObservedActualShard := shard == provider.observedActualShard
 	isObservedProjectedShard := pubKey[len(pubKey)-1] == byte(provider.observedProjectedShard)
 
@@ -454,6 +734,31 @@ func (provider *networkProvider) IsAddressObserved(address string) (bool, error)
 	return isObservedActualShard, nil
 }
 
+// IsContractAddressTracked returns whether balance-changing operations affecting the given smart contract address should be emitted
+func (provider *networkProvider) IsContractAddressTracked(address string) bool {
+	pubkey, err := provider.ConvertAddressToPubKey(address)
+	if err != nil {
+		return false
+	}
+
+	// When observing the metachain, the system smart contracts (e.g. staking, delegation manager, ESDT) are always tracked,
+	// since they are the only accounts there.
+	if provider.isObservingMetachain() && provider.isMetachainContractPubkey(pubkey) {
+		return true
+	}
+
+	return provider.trackedContracts.isTracked(address, pubkey)
+}
+
+func (provider *networkProvider) isObservingMetachain() bool {
+	return provider.observedActualShard == core.MetachainShardId
+}
+
+func (provider *networkProvider) isMetachainContractPubkey(pubkey []byte) bool {
+	shard, err := provider.baseProcessor.ComputeShardId(pubkey)
+	return err == nil && shard == core.MetachainShardId && isContractPubkey(pubkey)
+}
+
 // ConvertPubKeyToAddress converts a public key to an address
 func (provider *networkProvider) ConvertPubKeyToAddress(pubkey []byte) string {
 	return provider.pubKeyConverter.Encode(pubkey)
@@ -465,8 +770,30 @@ func (provider *networkProvider) ConvertAddressToPubKey(address string) ([]byte,
 }
 
 // ComputeTransactionHash computes the hash of a provided transaction
+// (the "options" field is taken into account, as well - e.g. for transactions signed by hash).
 func (provider *networkProvider) ComputeTransactionHash(tx *data.Transaction) (string, error) {
-	return provider.transactionProcessor.ComputeTransactionHash(tx)
+	protocolTx, err := signing.ToProtocolTransaction(tx, provider.pubKeyConverter)
+	if err != nil {
+		return "", newErrCannotComputeTransactionHash(err)
+	}
+
+	txHash, err := core.CalculateHash(provider.marshalizerForHashing, provider.hasher, protocolTx)
+	if err != nil {
+		return "", newErrCannotComputeTransactionHash(err)
+	}
+
+	return hex.EncodeToString(txHash), nil
+}
+
+// ComputeTransactionSigningPayload computes the bytes to be signed for a provided transaction, the same way the Node does
+// when verifying the signature: the canonical (JSON) serialization of the transaction or, for transactions signed by hash, its hash.
+func (provider *networkProvider) ComputeTransactionSigningPayload(tx *data.Transaction) ([]byte, error) {
+	payload, err := provider.signingPayloadComputer.ComputeSigningPayload(tx)
+	if err != nil {
+		return nil, newErrCannotComputeTransactionSigningPayload(err)
+	}
+
+	return payload, nil
 }
 
 func (provider *networkProvider) ComputeReceiptHash(apiReceipt *transaction.ApiReceipt) (string, error) {
@@ -511,6 +838,37 @@ func (provider *networkProvider) SendTransaction(tx *data.Transaction) (string,
 	return hash, nil
 }
 
+// SimulateTransactionCost asks the observer to simulate a transaction (e.g. a smart contract call), in order to estimate its cost (gas units).
+// If the simulation fails (e.g. the contract signals an error), a return message is provided (the gas units are 0).
+func (provider *networkProvider) SimulateTransactionCost(tx *data.Transaction) (*data.TxCostResponseData, error) {
+	if provider.isOffline {
+		return nil, errIsOffline
+	}
+
+	cost, err := provider.doSimulateTransactionCost(tx)
+	if err != nil {
+		log.Warn("SimulateTransactionCost()", "sender", tx.Sender, "receiver", tx.Receiver, "err", err)
+		return nil, err
+	}
+
+	log.Trace("SimulateTransactionCost()", "sender", tx.Sender, "receiver", tx.Receiver, "gasUnits", cost.TxCost, "returnMessage", cost.RetMessage)
+	return cost, nil
+}
+
+func (provider *networkProvider) doSimulateTransactionCost(tx *data.Transaction) (*data.TxCostResponseData, error) {
+	response := &data.ResponseTxCost{}
+
+	_, err := provider.baseProcessor.CallPostRestEndPoint(provider.getObserverUrl(), urlPathSimulateTxCost, tx, response)
+	if err != nil {
+		return nil, newErrCannotSimulateTransactionCost(convertStructuredApiErrToFlatErr(err))
+	}
+	if response.Error != "" {
+		return nil, newErrCannotSimulateTransactionCost(errors.New(response.Error))
+	}
+
+	return &response.Data, nil
+}
+
 // GetMempoolTransactionByHash gets a transaction from the pool
 func (provider *networkProvider) GetMempoolTransactionByHash(hash string) (*data.FullTransaction, error) {
 	if provider.isOffline {
@@ -529,25 +887,72 @@ func (provider *networkProvider) GetMempoolTransactionByHash(hash string) (*data
 	return nil, nil
 }
 
+// GetMempoolTransactions gets the (regular) transactions in the pool of the observer
+func (provider *networkProvider) GetMempoolTransactions() ([]*resources.TransactionInPool, error) {
+	if provider.isOffline {
+		return nil, errIsOffline
+	}
+
+	txs, err := provider.doGetMempoolTransactions()
+	if err != nil {
+		log.Warn("GetMempoolTransactions()", "err", err)
+		return nil, err
+	}
+
+	log.Trace("GetMempoolTransactions()", "numTxs", len(txs))
+
+	return txs, nil
+}
+
+func (provider *networkProvider) doGetMempoolTransactions() ([]*resources.TransactionInPool, error) {
+	response := &resources.TransactionsPoolApiResponse{}
+
+	_, err := provider.baseProcessor.CallGetRestEndPoint(provider.getObserverUrl(), urlPathGetTransactionPool, &response)
+	if err != nil {
+		return nil, newErrCannotGetTransactionsPool(convertStructuredApiErrToFlatErr(err))
+	}
+	if response.Error != "" {
+		return nil, newErrCannotGetTransactionsPool(errors.New(response.Error))
+	}
+
+	txs := make([]*resources.TransactionInPool, 0, len(response.Data.TxPool.RegularTransactions))
+	for _, wrapper := range response.Data.TxPool.RegularTransactions {
+		tx := wrapper.TxFields
+		txs = append(txs, &tx)
+	}
+
+	return txs, nil
+}
+
 // ComputeTransactionFeeForMoveBalance computes the fee for a move-balance transaction.
-// TODO: when freeze account feature is merged, this will need to be adapted as well, as for guarded transactions we have an additional gas (limit).
+// TODO: when the guardians feature is available in our dependencies (guardian fields of transactions, in elrond-go-core and elrond-proxy-go),
+// this will need to be adapted as well, as for guarded transactions we have an additional gas (limit). At the moment, guarded transactions
+// cannot be recognized (nor constructed, hashed or submitted) by this implementation.
 func (provider *networkProvider) ComputeTransactionFeeForMoveBalance(tx *data.FullTransaction) *big.Int {
-	minGasLimit := provider.networkConfig.MinGasLimit
-	gasPerDataByte := provider.networkConfig.GasPerDataByte
-	gasLimit := minGasLimit + gasPerDataByte*uint64(len(tx.Data))
+	return computeTransactionFeeForMoveBalance(tx, provider.GetNetworkConfig())
+}
 
-	fee := core.SafeMul(gasLimit, tx.GasPrice)
-	return fee
+// ComputeTransactionFee computes the fee of a transaction, given its gas limit (i.e. the fee initially paid, before any refund).
+// The gas needed for the "data movement" is charged at the full gas price, while the rest of the gas limit (for "execution") is charged at a reduced price
+// (given by the gas price modifier).
+func (provider *networkProvider) ComputeTransactionFee(tx *data.FullTransaction) *big.Int {
+	return computeTransactionFee(tx, provider.GetNetworkConfig())
 }
 
 // LogDescription writes a description of the network provider in the log output
 func (provider *networkProvider) LogDescription() {
 	log.Info("Description of network provider",
 		"isOffline", provider.isOffline,
-		"observerUrl", provider.observerUrl,
+		"observerUrls", provider.observerUrls,
 		"observedActualShard", provider.observedActualShard,
 		"observedProjectedShard", provider.observedProjectedShard,
 		"observedProjectedShardIsSet", provider.observedProjectedShardIsSet,
 		"nativeCurrency", provider.nativeCurrencySymbol,
+		"numCustomCurrencies", len(provider.customCurrencies.getAll()),
+		"trackAllContracts", provider.trackedContracts.trackAll,
+		"numTrackedContracts", provider.trackedContracts.numTracked(),
+		"maxInlinedTransactions", provider.maxInlinedTransactions,
+		"gasLimitSafetyMargin", provider.gasLimitSafetyMargin,
+		"historicalBalanceLookup", provider.historicalBalanceLookup,
 	)
 }
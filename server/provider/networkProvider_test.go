@@ -0,0 +1,366 @@
+package provider
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ElrondNetwork/elrond-go-core/core"
+	"github.com/ElrondNetwork/elrond-proxy-go/data"
+	"github.com/ElrondNetwork/rosetta/testscommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkProvider_ComputeTransactionHash(t *testing.T) {
+	args := createArgsNewNetworkProvider()
+	args.IsOffline = true
+
+	provider, err := NewNetworkProvider(args)
+	require.Nil(t, err)
+
+	tx := &data.Transaction{
+		Nonce:     42,
+		Value:     "1234",
+		Receiver:  "erd1spyavw0956vq68xj8y4tenjpq2wd5a9p2c6j8gsz7ztyrnpxrruqzu66jx",
+		Sender:    "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th",
+		GasPrice:  1000000000,
+		GasLimit:  57500,
+		Data:      []byte("hello"),
+		Signature: "aabb",
+		ChainID:   "T",
+		Version:   1,
+	}
+
+	// Same as the hash computed by the Proxy (which ignores the options)
+	hash, err := provider.ComputeTransactionHash(tx)
+	require.Nil(t, err)
+	expectedHash, err := provider.transactionProcessor.ComputeTransactionHash(tx)
+	require.Nil(t, err)
+	require.Equal(t, expectedHash, hash)
+
+	// Options are taken into account
+	tx.Version = 2
+	tx.Options = 1
+	hashWithOptions, err := provider.ComputeTransactionHash(tx)
+	require.Nil(t, err)
+	require.NotEqual(t, hash, hashWithOptions)
+
+	tx.Value = "foobar"
+	_, err = provider.ComputeTransactionHash(tx)
+	require.ErrorIs(t, err, errCannotComputeTransactionHash)
+}
+
+func TestNetworkProvider_ComputeTransactionSigningPayload(t *testing.T) {
+	args := createArgsNewNetworkProvider()
+	args.IsOffline = true
+
+	provider, err := NewNetworkProvider(args)
+	require.Nil(t, err)
+
+	// Secret key of the (publicly known) test wallet "alice", erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th
+	aliceSecretKey, _ := hex.DecodeString("413f42575f7f26fad3317a778771212fdb80245850981e48b58a4f25e344e8f9")
+	alicePrivateKey := ed25519.NewKeyFromSeed(aliceSecretKey)
+	alicePublicKey := alicePrivateKey.Public().(ed25519.PublicKey)
+
+	t.Run("with data, usernames and options", func(t *testing.T) {
+		tx := &data.Transaction{
+			Nonce:            42,
+			Value:            "01234",
+			Receiver:         "erd1spyavw0956vq68xj8y4tenjpq2wd5a9p2c6j8gsz7ztyrnpxrruqzu66jx",
+			Sender:           "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th",
+			SenderUsername:   []byte("alice"),
+			ReceiverUsername: []byte("bob"),
+			GasPrice:         1000000000,
+			GasLimit:         70000,
+			Data:             []byte("hello"),
+			Signature:        "aabb",
+			ChainID:          "T",
+			Version:          2,
+			Options:          2,
+		}
+
+		// Field order and omitted fields follow the Node's serialization, the value is normalized, the signature is never included
+		payload, err := provider.ComputeTransactionSigningPayload(tx)
+		require.Nil(t, err)
+		require.Equal(t, `{"nonce":42,"value":"1234","receiver":"erd1spyavw0956vq68xj8y4tenjpq2wd5a9p2c6j8gsz7ztyrnpxrruqzu66jx","sender":"erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th","senderUsername":"YWxpY2U=","receiverUsername":"Ym9i","gasPrice":1000000000,"gasLimit":70000,"data":"aGVsbG8=","chainID":"T","version":2,"options":2}`, string(payload))
+
+		signature := ed25519.Sign(alicePrivateKey, payload)
+		require.Equal(t, "f419fd3e9ac17be882910776030557d861975e2928dc4fc86bae2501d236710be04111414e059679db5e4d899704d9ca8e76e417f1a5ac70c435f64994ba8109", hex.EncodeToString(signature))
+	})
+
+	t.Run("without data", func(t *testing.T) {
+		tx := &data.Transaction{
+			Nonce:    7,
+			Value:    "0",
+			Receiver: "erd1spyavw0956vq68xj8y4tenjpq2wd5a9p2c6j8gsz7ztyrnpxrruqzu66jx",
+			Sender:   "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th",
+			GasPrice: 1000000000,
+			GasLimit: 50000,
+			ChainID:  "1",
+			Version:  1,
+		}
+
+		payload, err := provider.ComputeTransactionSigningPayload(tx)
+		require.Nil(t, err)
+		require.Equal(t, `{"nonce":7,"value":"0","receiver":"erd1spyavw0956vq68xj8y4tenjpq2wd5a9p2c6j8gsz7ztyrnpxrruqzu66jx","sender":"erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th","gasPrice":1000000000,"gasLimit":50000,"chainID":"1","version":1}`, string(payload))
+
+		signature := ed25519.Sign(alicePrivateKey, payload)
+		require.Equal(t, "f77ff8a1350606d45fc844b8efb71aef03301207a1bf6ae4f5eba64c01dfb3bcf832f189744b9a67aee9684bc942a9033553c14efcd45624ad897f1c8abc0903", hex.EncodeToString(signature))
+		require.True(t, ed25519.Verify(alicePublicKey, payload, signature))
+	})
+
+	t.Run("signed by hash", func(t *testing.T) {
+		tx := &data.Transaction{
+			Nonce:    7,
+			Value:    "0",
+			Receiver: "erd1spyavw0956vq68xj8y4tenjpq2wd5a9p2c6j8gsz7ztyrnpxrruqzu66jx",
+			Sender:   "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th",
+			GasPrice: 1000000000,
+			GasLimit: 50000,
+			ChainID:  "1",
+			Version:  2,
+			Options:  1,
+		}
+
+		payload, err := provider.ComputeTransactionSigningPayload(tx)
+		require.Nil(t, err)
+		require.Equal(t, "5556e2a9bd009f0553d114c85675c39784c053c09021590b63f2436f09d1e8d5", hex.EncodeToString(payload))
+	})
+
+	t.Run("with bad value", func(t *testing.T) {
+		_, err := provider.ComputeTransactionSigningPayload(&data.Transaction{Value: "foobar"})
+		require.ErrorIs(t, err, errCannotComputeTransactionSigningPayload)
+	})
+}
+
+// The transactions below have been signed by other tools (not by Rosetta), and their signatures and hashes are the ones accepted by the Node.
+// Vectors taken from elrond-go (v1.3.27): "integrationTests/frontend/wallet/txInterception_test.go" (transactions generated by the web wallet,
+// intercepted by a Node) and "examples/construction_test.go" (signatures and hashes).
+func TestNetworkProvider_ComputeTransactionSigningPayloadAndHashOfExternallySignedTransactions(t *testing.T) {
+	args := createArgsNewNetworkProvider()
+	args.IsOffline = true
+
+	provider, err := NewNetworkProvider(args)
+	require.Nil(t, err)
+
+	testCases := []struct {
+		name      string
+		tx        *data.Transaction
+		signature string
+		hash      string
+	}{
+		{
+			name: "web wallet, version 1, no data",
+			tx: &data.Transaction{
+				Nonce:    0,
+				Value:    "999",
+				Receiver: "erd12dnfhej64s6c56ka369gkyj3hwv5ms0y5rxgsk2k7hkd2vuk7rvqxkalsa",
+				Sender:   "erd1l20m7kzfht5rhdnd4zvqr82egk7m4nvv3zk06yw82zqmrt9kf0zsf9esqq",
+				GasPrice: 10,
+				GasLimit: 100000,
+				ChainID:  "integration tests chain ID",
+				Version:  1,
+			},
+			signature: "394c6f1375f6511dd281465fb9dd7caf013b6512a8f8ac278bbe2151cbded89da28bd539bc1c1c7884835742712c826900c092edb24ac02de9015f0f494f6c0a",
+		},
+		{
+			name: "web wallet, version 1, with data",
+			tx: &data.Transaction{
+				Nonce:    0,
+				Value:    "999",
+				Receiver: "erd12dnfhej64s6c56ka369gkyj3hwv5ms0y5rxgsk2k7hkd2vuk7rvqxkalsa",
+				Sender:   "erd1vjdll4xqtyll7nx3xx83audv27k5ktppvcc8kddn2yhxhwrjadgqlvtz8w",
+				GasPrice: 10,
+				GasLimit: 100000,
+				Data:     []byte("data@~`!@#$^&*()_=[]{};'<>?,./|<>><!!!!!"),
+				ChainID:  "integration tests chain ID",
+				Version:  1,
+			},
+			signature: "258edbbec6f7b67f6747340da09a5f849fe9fde29758097e06684d261e5d92d3abafbe9b2e2879226d738b2223a82468642e9342032ff69798de69b0fd6ca304",
+		},
+		{
+			name: "web wallet, version 2, signed by hash",
+			tx: &data.Transaction{
+				Nonce:    1,
+				Value:    "1000000000000000000",
+				Receiver: "erd1ez0puv8mqsulwllnavfygfzqe5zveeqjwpr6dsm8egchkf449kjqf8udu6",
+				Sender:   "erd1ez0puv8mqsulwllnavfygfzqe5zveeqjwpr6dsm8egchkf449kjqf8udu6",
+				GasPrice: 1000000000,
+				GasLimit: 56000,
+				Data:     []byte("test"),
+				ChainID:  "integration tests chain ID",
+				Version:  2,
+				Options:  1,
+			},
+			signature: "89cb10cafb75040d704b66610990c2ec7f6393e8da7ac867b1db417a9c9a3340947d5139e23ef14ebe80fd33ce352458ede505c0532f0316ac85c44456c8bf06",
+		},
+		{
+			name: "version 1, no data, no value",
+			tx: &data.Transaction{
+				Nonce:    89,
+				Value:    "0",
+				Receiver: "erd1spyavw0956vq68xj8y4tenjpq2wd5a9p2c6j8gsz7ztyrnpxrruqzu66jx",
+				Sender:   "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th",
+				GasPrice: 1000000000,
+				GasLimit: 50000,
+				ChainID:  "local-testnet",
+				Version:  1,
+			},
+			signature: "b56769014f2bdc5cf9fc4a05356807d71fcf8775c819b0f1b0964625b679c918ffa64862313bfef86f99b38cb84fcdb16fa33ad6eb565276616723405cd8f109",
+			hash:      "eb30c50c8831885ebcfac986d27e949ec02cf25676e22a009b7a486e5431ec2e",
+		},
+		{
+			name: "version 1, with data, no value",
+			tx: &data.Transaction{
+				Nonce:    90,
+				Value:    "0",
+				Receiver: "erd1spyavw0956vq68xj8y4tenjpq2wd5a9p2c6j8gsz7ztyrnpxrruqzu66jx",
+				Sender:   "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th",
+				GasPrice: 1000000000,
+				GasLimit: 80000,
+				Data:     []byte("hello"),
+				ChainID:  "local-testnet",
+				Version:  1,
+			},
+			signature: "e47fd437fc17ac9a69f7bf5f85bafa9e7628d851c4f69bd9fedc7e36029708b2e6d168d5cd652ea78beedd06d4440974ca46c403b14071a1a148d4188f6f2c0d",
+			hash:      "95ed9ac933712d7d77721d75eecfc7896873bb0d746417153812132521636872",
+		},
+		{
+			name: "version 1, with data, with value",
+			tx: &data.Transaction{
+				Nonce:    91,
+				Value:    "10000000000000000000",
+				Receiver: "erd1spyavw0956vq68xj8y4tenjpq2wd5a9p2c6j8gsz7ztyrnpxrruqzu66jx",
+				Sender:   "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th",
+				GasPrice: 1000000000,
+				GasLimit: 100000,
+				Data:     []byte("for the book"),
+				ChainID:  "local-testnet",
+				Version:  1,
+			},
+			signature: "9074789e0b4f9b2ac24b1fd351a4dd840afcfeb427b0f93e2a2d429c28c65ee9f4c288ca4dbde79de0e5bcf8c1a5d26e1b1c86203faea923e0edefb0b5099b0c",
+			hash:      "af53e0fc86612d5068862716b5169effdf554951ecc89849b0e836eb0b63fa3e",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			senderPubkey, err := provider.ConvertAddressToPubKey(testCase.tx.Sender)
+			require.Nil(t, err)
+			signature, err := hex.DecodeString(testCase.signature)
+			require.Nil(t, err)
+
+			payload, err := provider.ComputeTransactionSigningPayload(testCase.tx)
+			require.Nil(t, err)
+			require.True(t, ed25519.Verify(senderPubkey, payload, signature))
+
+			if len(testCase.hash) == 0 {
+				return
+			}
+
+			testCase.tx.Signature = testCase.signature
+			hash, err := provider.ComputeTransactionHash(testCase.tx)
+			require.Nil(t, err)
+			require.Equal(t, testCase.hash, hash)
+		})
+	}
+}
+
+func TestNetworkProvider_GetNodeStatusAndLatestBlockSummary(t *testing.T) {
+	numNodeStatusRequests := uint32(0)
+
+	// The observer advances by one block for each query of its status
+	observer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/node/status":
+			nonce := 100 + atomic.AddUint32(&numNodeStatusRequests, 1)
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"data":{"metrics":{"erd_nonce":%d,"erd_highest_final_nonce":%d,"erd_probable_highest_nonce":%d}},"error":"","code":"successful"}`, nonce, nonce, nonce)))
+		case strings.HasPrefix(r.URL.Path, "/block/by-nonce/"):
+			blockNonce := strings.TrimPrefix(r.URL.Path, "/block/by-nonce/")
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"data":{"block":{"nonce":%s,"hash":"hash-%s"}},"error":"","code":"successful"}`, blockNonce, blockNonce)))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer observer.Close()
+
+	args := createArgsNewNetworkProvider()
+	args.ObserverUrls = []string{observer.URL}
+	args.ObserverPubkeys = []string{"abba"}
+
+	provider, err := NewNetworkProvider(args)
+	require.Nil(t, err)
+
+	nodeStatus, latestBlockSummary, err := provider.GetNodeStatusAndLatestBlockSummary()
+	require.Nil(t, err)
+	require.Equal(t, uint32(1), atomic.LoadUint32(&numNodeStatusRequests))
+	require.Equal(t, uint64(101), nodeStatus.HighestNonce)
+	require.Equal(t, uint64(100), latestBlockSummary.Nonce)
+	require.Equal(t, "hash-100", latestBlockSummary.Hash)
+	// The observer does not report its pubkey, thus the configured one is used
+	require.Equal(t, "abba", nodeStatus.PublicKeyBlockSign)
+}
+
+func createArgsNewNetworkProvider() ArgsNewNetworkProvider {
+	return ArgsNewNetworkProvider{
+		NumShards:            3,
+		ObserverUrls:         []string{"http://localhost:8080"},
+		NativeCurrencySymbol: "EGLD",
+	}
+}
+
+func TestNetworkProvider_ObservingMetachain(t *testing.T) {
+	stakingContract := "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqplllst77y4l"
+	esdtContract := "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqzllls8a5w6u"
+	userInShard := testscommon.TestAddressAlice
+	contractInShard := testscommon.TestAddressOfContract
+
+	args := createArgsNewNetworkProvider()
+	args.ObservedActualShard = core.MetachainShardId
+
+	metachainProvider, err := NewNetworkProvider(args)
+	require.Nil(t, err)
+
+	args.ObservedActualShard = 0
+	shardProvider, err := NewNetworkProvider(args)
+	require.Nil(t, err)
+
+	t.Run("addresses are observed either on the metachain or on a shard", func(t *testing.T) {
+		for _, address := range []string{stakingContract, esdtContract} {
+			isObserved, err := metachainProvider.IsAddressObserved(address)
+			require.Nil(t, err)
+			require.True(t, isObserved)
+
+			isObserved, err = shardProvider.IsAddressObserved(address)
+			require.Nil(t, err)
+			require.False(t, isObserved)
+		}
+
+		for _, address := range []string{userInShard, contractInShard} {
+			isObserved, err := metachainProvider.IsAddressObserved(address)
+			require.Nil(t, err)
+			require.False(t, isObserved)
+		}
+	})
+
+	t.Run("system contracts are tracked when observing the metachain", func(t *testing.T) {
+		require.True(t, metachainProvider.IsContractAddressTracked(stakingContract))
+		require.True(t, metachainProvider.IsContractAddressTracked(esdtContract))
+		require.False(t, metachainProvider.IsContractAddressTracked(contractInShard))
+		require.False(t, metachainProvider.IsContractAddressTracked(userInShard))
+		require.False(t, shardProvider.IsContractAddressTracked(stakingContract))
+	})
+
+	t.Run("projected shard cannot be set", func(t *testing.T) {
+		args.ObservedActualShard = core.MetachainShardId
+		args.ObservedProjectedShardIsSet = true
+
+		_, err := NewNetworkProvider(args)
+		require.Equal(t, errProjectedShardOfMetachain, err)
+	})
+}
@@ -0,0 +1,28 @@
+package provider
+
+// getObserverUrl returns the URL of the observer to be queried: the first one that is synced (as given by the health checks).
+// If none of the observers is synced, the underlying nodes provider still returns one of them (requests are not refused upfront).
+func (provider *networkProvider) getObserverUrl() string {
+	observers, err := provider.baseProcessor.GetObservers(provider.observedActualShard)
+	if err != nil || len(observers) == 0 {
+		log.Error("getObserverUrl(): no observer available, falling back to the first configured one", "err", err)
+		return provider.observerUrls[0]
+	}
+
+	return observers[0].Address
+}
+
+// StartObserversHealthChecks starts checking (in the background) the synchronization state of the observers, using their node status.
+// Out-of-sync (or unreachable) observers are not queried until they recover. Checks are also triggered when an observer cannot be reached.
+func (provider *networkProvider) StartObserversHealthChecks() {
+	if provider.isOffline {
+		return
+	}
+
+	provider.syncStateChecker.StartNodesSyncStateChecks()
+}
+
+// Close stops the health checks of the observers
+func (provider *networkProvider) Close() error {
+	return provider.syncStateChecker.Close()
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createObserverForFailoverTests(nonce uint64, probableHighestNonce uint64, numRequests *uint32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddUint32(numRequests, 1)
+
+		switch {
+		case r.URL.Path == "/node/status":
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"data":{"metrics":{"erd_nonce":%d,"erd_highest_final_nonce":%d,"erd_probable_highest_nonce":%d,"erd_are_vm_queries_ready":"true"}},"error":"","code":"successful"}`, nonce, nonce, probableHighestNonce)))
+		case strings.HasPrefix(r.URL.Path, "/block/by-nonce/"):
+			blockNonce := strings.TrimPrefix(r.URL.Path, "/block/by-nonce/")
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"data":{"block":{"nonce":%s,"hash":"hash-%s","miniBlocks":[{"processingType":"Scheduled"}]}},"error":"","code":"successful"}`, blockNonce, blockNonce)))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestNetworkProvider_ObserversFailover(t *testing.T) {
+	numRequestsToLagging := uint32(0)
+	numRequestsToSynced := uint32(0)
+
+	laggingObserver := createObserverForFailoverTests(10, 100, &numRequestsToLagging)
+	defer laggingObserver.Close()
+
+	syncedObserver := createObserverForFailoverTests(100, 100, &numRequestsToSynced)
+	defer syncedObserver.Close()
+
+	args := createArgsNewNetworkProvider()
+	args.ObserverUrls = []string{laggingObserver.URL, syncedObserver.URL}
+
+	provider, err := NewNetworkProvider(args)
+	require.Nil(t, err)
+
+	// Before the first health check, all observers are considered synced
+	require.Equal(t, laggingObserver.URL, provider.getObserverUrl())
+
+	provider.StartObserversHealthChecks()
+	defer func() {
+		_ = provider.Close()
+	}()
+
+	require.Eventually(t, func() bool {
+		return provider.getObserverUrl() == syncedObserver.URL
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// All the queries of a /block call (latest nonce, the block, its neighbours) go to the same observer
+	atomic.StoreUint32(&numRequestsToLagging, 0)
+	atomic.StoreUint32(&numRequestsToSynced, 0)
+
+	block, err := provider.GetBlockByNonce(42)
+	require.Nil(t, err)
+	require.Equal(t, "hash-42", block.Hash)
+	require.Equal(t, uint32(0), atomic.LoadUint32(&numRequestsToLagging))
+	require.Equal(t, uint32(4), atomic.LoadUint32(&numRequestsToSynced))
+}
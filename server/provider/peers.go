@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ElrondNetwork/elrond-proxy-go/data"
+)
+
+// GetPeers gets the peers known by the observer (as given by its heartbeat status), excluding the inactive ones and the ones
+// in other shards. At most "maxNumPeers" are returned. Since the heartbeat status is large, the peers are cached for a while.
+// The heartbeat status is fetched without holding the lock (concurrent callers might fetch it at the same time, when the cache expires).
+func (provider *networkProvider) GetPeers() ([]data.PubKeyHeartbeat, error) {
+	if provider.isOffline {
+		return nil, errIsOffline
+	}
+
+	peers, ok := provider.getCachedPeers()
+	if ok {
+		return peers, nil
+	}
+
+	heartbeats, err := provider.doGetHeartbeats()
+	if err != nil {
+		log.Warn("GetPeers()", "err", err)
+		return nil, err
+	}
+
+	peers = make([]data.PubKeyHeartbeat, 0, maxNumPeers)
+	for _, heartbeat := range heartbeats {
+		if len(peers) == maxNumPeers {
+			break
+		}
+		if heartbeat.IsActive && heartbeat.ComputedShardID == provider.observedActualShard {
+			peers = append(peers, heartbeat)
+		}
+	}
+
+	provider.peersMutex.Lock()
+	provider.peers = peers
+	provider.peersTimestamp = time.Now()
+	provider.peersMutex.Unlock()
+
+	log.Trace("GetPeers()", "numHeartbeats", len(heartbeats), "numPeers", len(peers))
+	return peers, nil
+}
+
+func (provider *networkProvider) getCachedPeers() ([]data.PubKeyHeartbeat, bool) {
+	provider.peersMutex.Lock()
+	defer provider.peersMutex.Unlock()
+
+	if provider.peers != nil && time.Since(provider.peersTimestamp) < time.Duration(peersCacheTTLInSeconds)*time.Second {
+		return provider.peers, true
+	}
+
+	return nil, false
+}
+
+func (provider *networkProvider) doGetHeartbeats() ([]data.PubKeyHeartbeat, error) {
+	response := &data.HeartbeatApiResponse{}
+
+	_, err := provider.baseProcessor.CallGetRestEndPoint(provider.getObserverUrl(), urlPathGetHeartbeatStatus, response)
+	if err != nil {
+		return nil, newErrCannotGetPeers(convertStructuredApiErrToFlatErr(err))
+	}
+	if response.Error != "" {
+		return nil, newErrCannotGetPeers(errors.New(response.Error))
+	}
+
+	return response.Data.Heartbeats, nil
+}
+
+// VerifyObserverPubkeys checks the configured pubkey of each observer against the one reported by the observer itself.
+// If no pubkey is configured for an observer (or the placeholder, all-zeros one), the reported pubkey is adopted.
+// If an observer cannot be reached, its configured pubkey is kept as it is (a warning is logged).
+func (provider *networkProvider) VerifyObserverPubkeys() error {
+	if provider.isOffline {
+		return errIsOffline
+	}
+
+	for _, observerUrl := range provider.observerUrls {
+		err := provider.verifyObserverPubkey(observerUrl)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (provider *networkProvider) verifyObserverPubkey(observerUrl string) error {
+	nodeStatus, err := provider.getNodeStatus(observerUrl)
+	if err != nil {
+		log.Warn("verifyObserverPubkey(): cannot get node status, pubkey not verified", "observer", observerUrl, "err", err)
+		return nil
+	}
+
+	reportedPubkey := nodeStatus.PublicKeyBlockSign
+	if reportedPubkey == "" {
+		log.Warn("verifyObserverPubkey(): observer does not report its pubkey, pubkey not verified", "observer", observerUrl)
+		return nil
+	}
+
+	provider.observerPubkeysMutex.Lock()
+	defer provider.observerPubkeysMutex.Unlock()
+
+	configuredPubkey := provider.observerPubkeys[observerUrl]
+
+	if isPlaceholderPubkey(configuredPubkey) {
+		log.Info("verifyObserverPubkey(): adopting the pubkey reported by the observer", "observer", observerUrl, "pubkey", reportedPubkey)
+		provider.observerPubkeys[observerUrl] = reportedPubkey
+		return nil
+	}
+
+	if !strings.EqualFold(configuredPubkey, reportedPubkey) {
+		return newErrObserverPubkeyMismatch(observerUrl, configuredPubkey, reportedPubkey)
+	}
+
+	return nil
+}
+
+func (provider *networkProvider) getObserverPubkey(observerUrl string) string {
+	provider.observerPubkeysMutex.RLock()
+	defer provider.observerPubkeysMutex.RUnlock()
+
+	return provider.observerPubkeys[observerUrl]
+}
+
+func isPlaceholderPubkey(pubkey string) bool {
+	return strings.Trim(pubkey, "0") == ""
+}
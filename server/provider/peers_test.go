@@ -0,0 +1,179 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createObserverForPeersTests(numHeartbeatRequests *uint32) *httptest.Server {
+	return createObserverWithPubkeyForPeersTests("abba", numHeartbeatRequests)
+}
+
+func createObserverWithPubkeyForPeersTests(pubkey string, numHeartbeatRequests *uint32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/node/status":
+			_, _ = w.Write([]byte(`{"data":{"metrics":{"erd_nonce":42,"erd_public_key_block_sign":"` + pubkey + `","erd_shard_id":0,"erd_peer_type":"observer"}},"error":"","code":"successful"}`))
+		case "/node/heartbeatstatus":
+			atomic.AddUint32(numHeartbeatRequests, 1)
+			_, _ = w.Write([]byte(`{"data":{"heartbeats":[{"publicKey":"abba","computedShardID":0,"peerType":"observer","isActive":true},{"publicKey":"aaaa","computedShardID":0,"peerType":"eligible","isActive":true},{"publicKey":"bbbb","computedShardID":0,"peerType":"eligible","isActive":false},{"publicKey":"cccc","computedShardID":1,"peerType":"eligible","isActive":true}]},"error":"","code":"successful"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestNetworkProvider_GetPeers(t *testing.T) {
+	numHeartbeatRequests := uint32(0)
+	observer := createObserverForPeersTests(&numHeartbeatRequests)
+	defer observer.Close()
+
+	args := createArgsNewNetworkProvider()
+	args.ObserverUrls = []string{observer.URL}
+
+	provider, err := NewNetworkProvider(args)
+	require.Nil(t, err)
+
+	// Inactive peers and peers in other shards are excluded
+	peers, err := provider.GetPeers()
+	require.Nil(t, err)
+	require.Len(t, peers, 2)
+	require.Equal(t, "abba", peers[0].PublicKey)
+	require.Equal(t, "aaaa", peers[1].PublicKey)
+	require.Equal(t, "eligible", peers[1].PeerType)
+	require.Equal(t, uint32(1), atomic.LoadUint32(&numHeartbeatRequests))
+
+	// Peers are cached
+	peers, err = provider.GetPeers()
+	require.Nil(t, err)
+	require.Len(t, peers, 2)
+	require.Equal(t, uint32(1), atomic.LoadUint32(&numHeartbeatRequests))
+
+	// Once the cache expires, peers are fetched again
+	provider.peersTimestamp = time.Now().Add(-time.Duration(peersCacheTTLInSeconds) * time.Second)
+	peers, err = provider.GetPeers()
+	require.Nil(t, err)
+	require.Len(t, peers, 2)
+	require.Equal(t, uint32(2), atomic.LoadUint32(&numHeartbeatRequests))
+
+	args.ObserverUrls = []string{observer.URL + "/nowhere"}
+	provider, err = NewNetworkProvider(args)
+	require.Nil(t, err)
+
+	_, err = provider.GetPeers()
+	require.ErrorIs(t, err, errCannotGetPeers)
+}
+
+func TestNetworkProvider_GetPeersDoesNotHoldTheLockWhileFetching(t *testing.T) {
+	requestReceived := make(chan struct{})
+	releaseResponse := make(chan struct{})
+
+	observer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(requestReceived)
+		<-releaseResponse
+		_, _ = w.Write([]byte(`{"data":{"heartbeats":[{"publicKey":"aaaa","computedShardID":0,"peerType":"eligible","isActive":true}]},"error":"","code":"successful"}`))
+	}))
+	defer observer.Close()
+
+	args := createArgsNewNetworkProvider()
+	args.ObserverUrls = []string{observer.URL}
+
+	provider, err := NewNetworkProvider(args)
+	require.Nil(t, err)
+
+	done := make(chan error)
+	go func() {
+		_, err := provider.GetPeers()
+		done <- err
+	}()
+
+	<-requestReceived
+
+	// While the heartbeat status is being fetched, the cache can be read
+	cacheRead := make(chan struct{})
+	go func() {
+		_, _ = provider.getCachedPeers()
+		close(cacheRead)
+	}()
+
+	select {
+	case <-cacheRead:
+	case <-time.After(time.Second):
+		require.Fail(t, "lock held while fetching the heartbeat status")
+	}
+
+	close(releaseResponse)
+	require.Nil(t, <-done)
+
+	peers, ok := provider.getCachedPeers()
+	require.True(t, ok)
+	require.Len(t, peers, 1)
+}
+
+func TestNetworkProvider_VerifyObserverPubkeys(t *testing.T) {
+	firstObserver := createObserverWithPubkeyForPeersTests("abba", new(uint32))
+	defer firstObserver.Close()
+	secondObserver := createObserverWithPubkeyForPeersTests("cdcd", new(uint32))
+	defer secondObserver.Close()
+
+	args := createArgsNewNetworkProvider()
+	args.ObserverUrls = []string{firstObserver.URL, secondObserver.URL}
+
+	t.Run("placeholder (or missing) pubkeys are replaced by the reported ones", func(t *testing.T) {
+		args.ObserverPubkeys = []string{"0000000000000000000000000000000000000000000000000000000000000000"}
+		provider, err := NewNetworkProvider(args)
+		require.Nil(t, err)
+
+		err = provider.VerifyObserverPubkeys()
+		require.Nil(t, err)
+		require.Equal(t, "abba", provider.getObserverPubkey(firstObserver.URL))
+		require.Equal(t, "cdcd", provider.getObserverPubkey(secondObserver.URL))
+	})
+
+	t.Run("matching pubkeys", func(t *testing.T) {
+		args.ObserverPubkeys = []string{"ABBA", "cdcd"}
+		provider, err := NewNetworkProvider(args)
+		require.Nil(t, err)
+
+		err = provider.VerifyObserverPubkeys()
+		require.Nil(t, err)
+		require.Equal(t, "ABBA", provider.getObserverPubkey(firstObserver.URL))
+		require.Equal(t, "cdcd", provider.getObserverPubkey(secondObserver.URL))
+	})
+
+	t.Run("each observer is verified", func(t *testing.T) {
+		args.ObserverPubkeys = []string{"abba", "abba"}
+		provider, err := NewNetworkProvider(args)
+		require.Nil(t, err)
+
+		err = provider.VerifyObserverPubkeys()
+		require.ErrorIs(t, err, errObserverPubkeyMismatch)
+		require.Contains(t, err.Error(), secondObserver.URL)
+	})
+
+	t.Run("unreachable observer", func(t *testing.T) {
+		unreachableObserverUrl := firstObserver.URL + "/nowhere"
+
+		args := createArgsNewNetworkProvider()
+		args.ObserverUrls = []string{unreachableObserverUrl, secondObserver.URL}
+		args.ObserverPubkeys = []string{"aaaa"}
+		provider, err := NewNetworkProvider(args)
+		require.Nil(t, err)
+
+		err = provider.VerifyObserverPubkeys()
+		require.Nil(t, err)
+		require.Equal(t, "aaaa", provider.getObserverPubkey(unreachableObserverUrl))
+		require.Equal(t, "cdcd", provider.getObserverPubkey(secondObserver.URL))
+	})
+
+	t.Run("more pubkeys than observers", func(t *testing.T) {
+		args.ObserverPubkeys = []string{"abba", "cdcd", "eeee"}
+		_, err := NewNetworkProvider(args)
+		require.ErrorIs(t, err, errTooManyObserverPubkeys)
+	})
+}
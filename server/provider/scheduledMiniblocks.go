@@ -5,17 +5,19 @@ import (
 	"github.com/ElrondNetwork/elrond-proxy-go/data"
 )
 
-func (provider *networkProvider) simplifyBlockWithScheduledTransactions(block *data.Block) error {
+// simplifyBlockWithScheduledTransactions reconstructs the effects of a block, given its neighbouring blocks (N-1, N+1).
+// The neighbouring blocks must be fetched from the same observer as the block itself, for a consistent view.
+func (provider *networkProvider) simplifyBlockWithScheduledTransactions(observerUrl string, block *data.Block) error {
 	if hasOnlyNormalMiniblocks(block) {
 		return nil
 	}
 
-	previousBlock, err := provider.doGetBlockByNonce(block.Nonce - 1)
+	previousBlock, err := provider.doGetBlockByNonce(observerUrl, block.Nonce-1)
 	if err != nil {
 		return err
 	}
 
-	nextBlock, err := provider.doGetBlockByNonce(block.Nonce + 1)
+	nextBlock, err := provider.doGetBlockByNonce(observerUrl, block.Nonce+1)
 	if err != nil {
 		return err
 	}
@@ -16,10 +16,11 @@ type NetworkConfigApiResponsePayload struct {
 
 // NetworkConfig is an API resource
 type NetworkConfig struct {
-	ChainID        string `json:"erd_chain_id"`
-	GasPerDataByte uint64 `json:"erd_gas_per_data_byte"`
-	MinGasPrice    uint64 `json:"erd_min_gas_price"`
-	MinGasLimit    uint64 `json:"erd_min_gas_limit"`
+	ChainID          string  `json:"erd_chain_id"`
+	GasPerDataByte   uint64  `json:"erd_gas_per_data_byte"`
+	MinGasPrice      uint64  `json:"erd_min_gas_price"`
+	MinGasLimit      uint64  `json:"erd_min_gas_limit"`
+	GasPriceModifier float64 `json:"erd_gas_price_modifier,string"`
 }
 
 // NodeStatusApiResponse is an API resource
@@ -36,8 +37,17 @@ type NodeStatusApiResponsePayload struct {
 
 // NodeStatus is an API resource
 type NodeStatus struct {
-	HighestNonce      uint64 `json:"erd_nonce"`
-	HighestFinalNonce uint64 `json:"erd_highest_final_nonce"`
+	HighestNonce         uint64 `json:"erd_nonce"`
+	HighestFinalNonce    uint64 `json:"erd_highest_final_nonce"`
+	ProbableHighestNonce uint64 `json:"erd_probable_highest_nonce"`
+	CurrentRound         uint64 `json:"erd_current_round"`
+	SynchronizedRound    uint64 `json:"erd_synchronized_round"`
+	IsSyncing            uint64 `json:"erd_is_syncing"`
+	PublicKeyBlockSign   string `json:"erd_public_key_block_sign"`
+	ShardID              uint64 `json:"erd_shard_id"`
+	PeerType             string `json:"erd_peer_type"`
+	AppVersion           string `json:"erd_app_version"`
+	NodeDisplayName      string `json:"erd_node_display_name"`
 }
 
 // BlockSummary is an internal resource
@@ -54,6 +64,130 @@ type NativeCurrency struct {
 	Decimals int32
 }
 
+// ObservedShard is an internal resource (describes a shard observed by a multi-shard instance)
+type ObservedShard struct {
+	// Shard is the shard ID (4294967295 for the metachain)
+	Shard uint32 `json:"shard"`
+	// ObserverUrls are the URLs of the observers of the shard (see failover)
+	ObserverUrls []string `json:"observerUrls"`
+	// ObserverPubkeys are optional, given in the same order as the URLs (if missing, the pubkeys reported by the observers are used)
+	ObserverPubkeys []string `json:"observerPubkeys"`
+	// GenesisBlock is the hash of the genesis block of the shard
+	GenesisBlock string `json:"genesisBlock"`
+}
+
+// CustomCurrency is an internal resource (describes an ESDT token)
+type CustomCurrency struct {
+	// Identifier is the token identifier (for fungible tokens) or the token identifier suffixed by the nonce (for the others)
+	Identifier string `json:"identifier"`
+	// Symbol is the currency symbol, as seen by the API consumer (defaults to the identifier)
+	Symbol   string `json:"symbol"`
+	Decimals int32  `json:"decimals"`
+	// ActivationNonce is the first block (nonce) for which operations (and balances) of the currency are reported
+	ActivationNonce uint64 `json:"activationNonce"`
+}
+
+// AccountQueryOptions is an internal resource (the block at which an account is fetched)
+type AccountQueryOptions struct {
+	OnFinalBlock  bool
+	BlockNonce    OptionalUint64
+	BlockHash     string
+	BlockRootHash string
+}
+
+// OptionalUint64 is an internal resource
+type OptionalUint64 struct {
+	Value    uint64
+	HasValue bool
+}
+
+// NewAccountQueryOptionsOnFinalBlock creates options for fetching an account at the latest final block
+func NewAccountQueryOptionsOnFinalBlock() AccountQueryOptions {
+	return AccountQueryOptions{OnFinalBlock: true}
+}
+
+// NewAccountQueryOptionsWithBlockNonce creates options for fetching an account at a given block (historical lookup)
+func NewAccountQueryOptionsWithBlockNonce(nonce uint64) AccountQueryOptions {
+	return AccountQueryOptions{BlockNonce: OptionalUint64{Value: nonce, HasValue: true}}
+}
+
+// NewAccountQueryOptionsWithBlockHash creates options for fetching an account at a given block (historical lookup)
+func NewAccountQueryOptionsWithBlockHash(hash string) AccountQueryOptions {
+	return AccountQueryOptions{BlockHash: hash}
+}
+
+// AccountESDTBalance is an internal resource
+type AccountESDTBalance struct {
+	Balance   string
+	BlockInfo data.BlockInfo
+}
+
+// AccountESDTBalanceApiResponse is an API resource
+type AccountESDTBalanceApiResponse struct {
+	Data  AccountESDTBalanceApiResponsePayload `json:"data"`
+	Error string                               `json:"error"`
+	Code  data.ReturnCode                      `json:"code"`
+}
+
+// AccountESDTBalanceApiResponsePayload is an API resource
+type AccountESDTBalanceApiResponsePayload struct {
+	TokenData AccountESDTTokenData `json:"tokenData"`
+	BlockInfo data.BlockInfo       `json:"blockInfo"`
+}
+
+// AccountAllESDTBalancesApiResponse is an API resource
+type AccountAllESDTBalancesApiResponse struct {
+	Data  AccountAllESDTBalancesApiResponsePayload `json:"data"`
+	Error string                                   `json:"error"`
+	Code  data.ReturnCode                          `json:"code"`
+}
+
+// AccountAllESDTBalancesApiResponsePayload is an API resource
+type AccountAllESDTBalancesApiResponsePayload struct {
+	Tokens    map[string]AccountESDTTokenData `json:"esdts"`
+	BlockInfo data.BlockInfo                  `json:"blockInfo"`
+}
+
+// AccountESDTTokenData is an API resource
+type AccountESDTTokenData struct {
+	Identifier string `json:"tokenIdentifier"`
+	Balance    string `json:"balance"`
+	Nonce      uint64 `json:"nonce"`
+}
+
+// TransactionsPoolApiResponse is an API resource
+type TransactionsPoolApiResponse struct {
+	Data  TransactionsPoolApiResponsePayload `json:"data"`
+	Error string                             `json:"error"`
+	Code  string                             `json:"code"`
+}
+
+// TransactionsPoolApiResponsePayload is an API resource
+type TransactionsPoolApiResponsePayload struct {
+	TxPool TransactionsPool `json:"txPool"`
+}
+
+// TransactionsPool is an API resource
+type TransactionsPool struct {
+	RegularTransactions []TransactionInPoolWrapper `json:"regularTransactions"`
+}
+
+// TransactionInPoolWrapper is an API resource
+type TransactionInPoolWrapper struct {
+	TxFields TransactionInPool `json:"txFields"`
+}
+
+// TransactionInPool is an API resource (only the fields of interest are requested)
+type TransactionInPool struct {
+	Hash     string `json:"hash"`
+	Sender   string `json:"sender"`
+	Receiver string `json:"receiver"`
+	Value    string `json:"value"`
+	Data     []byte `json:"data"`
+	GasLimit uint64 `json:"gaslimit"`
+	GasPrice uint64 `json:"gasprice"`
+}
+
 // GenesisBalancesApiResponse is an API resource
 type GenesisBalancesApiResponse struct {
 	Data  GenesisBalancesApiResponsePayload `json:"data"`
@@ -2,7 +2,11 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
+	"github.com/ElrondNetwork/elrond-proxy-go/data"
+	"github.com/ElrondNetwork/rosetta/server/resources"
 	"github.com/coinbase/rosetta-sdk-go/server"
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
@@ -27,20 +31,36 @@ func (service *accountService) AccountBalance(
 	_ context.Context,
 	request *types.AccountBalanceRequest,
 ) (*types.AccountBalanceResponse, *types.Error) {
-	if request.AccountIdentifier.Address == "" {
+	address := request.AccountIdentifier.Address
+	if address == "" {
 		return nil, service.errFactory.newErr(ErrInvalidAccountAddress)
 	}
 
-	accountModel, err := service.provider.GetAccount(request.AccountIdentifier.Address)
+	errTyped := service.checkBlockIdentifierOfRequest(request.BlockIdentifier)
+	if errTyped != nil {
+		return nil, errTyped
+	}
+
+	options := accountQueryOptionsFromBlockIdentifier(request.BlockIdentifier)
+
+	accountModel, err := service.provider.GetAccount(address, options)
 	if err != nil {
 		return nil, service.errFactory.newErrWithOriginal(ErrUnableToGetAccount, err)
 	}
 
+	err = checkBlockInfoMatchesBlockIdentifier(accountModel.BlockInfo, request.BlockIdentifier)
+	if err != nil {
+		return nil, service.errFactory.newErrWithOriginal(ErrUnableToGetAccount, err)
+	}
+
+	balances, errTyped := service.getBalances(address, accountModel, request.Currencies, options, request.BlockIdentifier)
+	if errTyped != nil {
+		return nil, errTyped
+	}
+
 	response := &types.AccountBalanceResponse{
 		BlockIdentifier: blockInfoToIdentifier(accountModel.BlockInfo),
-		Balances: []*types.Amount{
-			service.extension.valueToNativeAmount(accountModel.Account.Balance),
-		},
+		Balances:        balances,
 		Metadata: map[string]interface{}{
 			"nonce":    accountModel.Account.Nonce,
 			"username": accountModel.Account.Username,
@@ -50,6 +70,150 @@ func (service *accountService) AccountBalance(
 	return response, nil
 }
 
+func (service *accountService) getBalances(
+	address string,
+	accountModel *data.AccountModel,
+	currencies []*types.Currency,
+	options resources.AccountQueryOptions,
+	blockIdentifier *types.PartialBlockIdentifier,
+) ([]*types.Amount, *types.Error) {
+	// The custom currencies (ESDT) balances are fetched using the same query options as the native balance
+	// (thus, no historical query is performed unless explicitly requested).
+	if len(currencies) == 0 {
+		return service.getAllBalances(address, accountModel, options, blockIdentifier)
+	}
+
+	balances := make([]*types.Amount, 0, len(currencies))
+
+	for _, currency := range currencies {
+		balance, err := service.getBalanceOfCurrency(address, accountModel, currency, options, blockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+
+		balances = append(balances, balance)
+	}
+
+	return balances, nil
+}
+
+// getAllBalances returns the native balance, followed by the balances of the supported custom currencies held by the account
+func (service *accountService) getAllBalances(
+	address string,
+	accountModel *data.AccountModel,
+	options resources.AccountQueryOptions,
+	blockIdentifier *types.PartialBlockIdentifier,
+) ([]*types.Amount, *types.Error) {
+	balances := []*types.Amount{
+		service.extension.valueToNativeAmount(accountModel.Account.Balance),
+	}
+
+	blockNonce := accountModel.BlockInfo.Nonce
+	customCurrencies := service.provider.GetCustomCurrencies()
+	if len(customCurrencies) == 0 {
+		return balances, nil
+	}
+
+	esdtBalances, err := service.provider.GetAccountAllESDTBalances(address, options)
+	if err != nil {
+		return nil, service.errFactory.newErrWithOriginal(ErrUnableToGetAccount, err)
+	}
+
+	for _, currency := range customCurrencies {
+		if !isCustomCurrencyActive(currency, blockNonce) {
+			continue
+		}
+
+		esdtBalance, ok := esdtBalances[currency.Identifier]
+		if !ok {
+			continue
+		}
+
+		err = checkBlockInfoMatchesBlockIdentifier(esdtBalance.BlockInfo, blockIdentifier)
+		if err != nil {
+			return nil, service.errFactory.newErrWithOriginal(ErrUnableToGetAccount, err)
+		}
+
+		balances = append(balances, service.extension.valueToCustomAmount(esdtBalance.Balance, currency))
+	}
+
+	return balances, nil
+}
+
+func (service *accountService) getBalanceOfCurrency(
+	address string,
+	accountModel *data.AccountModel,
+	currency *types.Currency,
+	options resources.AccountQueryOptions,
+	blockIdentifier *types.PartialBlockIdentifier,
+) (*types.Amount, *types.Error) {
+	if service.extension.isNativeCurrency(currency) {
+		return service.extension.valueToNativeAmount(accountModel.Account.Balance), nil
+	}
+
+	customCurrency, ok := service.extension.getActiveCustomCurrencyBySymbol(currency.Symbol, accountModel.BlockInfo.Nonce)
+	if !ok || customCurrency.Decimals != currency.Decimals {
+		return nil, service.errFactory.newErr(ErrUnsupportedCurrency)
+	}
+
+	esdtBalance, err := service.provider.GetAccountESDTBalance(address, customCurrency.Identifier, options)
+	if err != nil {
+		return nil, service.errFactory.newErrWithOriginal(ErrUnableToGetAccount, err)
+	}
+
+	err = checkBlockInfoMatchesBlockIdentifier(esdtBalance.BlockInfo, blockIdentifier)
+	if err != nil {
+		return nil, service.errFactory.newErrWithOriginal(ErrUnableToGetAccount, err)
+	}
+
+	return service.extension.valueToCustomAmount(esdtBalance.Balance, customCurrency), nil
+}
+
+// checkBlockIdentifierOfRequest checks that a historical lookup is enabled (if requested), and that the block index is valid
+func (service *accountService) checkBlockIdentifierOfRequest(blockIdentifier *types.PartialBlockIdentifier) *types.Error {
+	if blockIdentifier == nil {
+		return nil
+	}
+	if !service.provider.IsHistoricalBalanceLookupEnabled() {
+		return service.errFactory.newErrWithOriginal(ErrInvalidInputParam, errors.New("historical balance lookup is not enabled"))
+	}
+	if blockIdentifier.Index != nil && *blockIdentifier.Index < 0 {
+		return service.errFactory.newErrWithOriginal(ErrInvalidInputParam, errors.New("block index cannot be negative"))
+	}
+
+	return nil
+}
+
+// accountQueryOptionsFromBlockIdentifier returns the options for fetching an account at the requested block (historical lookup),
+// or at the latest final block (if no block is requested)
+func accountQueryOptionsFromBlockIdentifier(blockIdentifier *types.PartialBlockIdentifier) resources.AccountQueryOptions {
+	if blockIdentifier == nil {
+		return resources.NewAccountQueryOptionsOnFinalBlock()
+	}
+	if blockIdentifier.Hash != nil {
+		return resources.NewAccountQueryOptionsWithBlockHash(*blockIdentifier.Hash)
+	}
+	if blockIdentifier.Index != nil {
+		return resources.NewAccountQueryOptionsWithBlockNonce(uint64(*blockIdentifier.Index))
+	}
+
+	return resources.NewAccountQueryOptionsOnFinalBlock()
+}
+
+func checkBlockInfoMatchesBlockIdentifier(blockInfo data.BlockInfo, blockIdentifier *types.PartialBlockIdentifier) error {
+	if blockIdentifier == nil {
+		return nil
+	}
+	if blockIdentifier.Index != nil && uint64(*blockIdentifier.Index) != blockInfo.Nonce {
+		return fmt.Errorf("%w: requested index = %d, actual index = %d", errBlockMismatch, *blockIdentifier.Index, blockInfo.Nonce)
+	}
+	if blockIdentifier.Hash != nil && *blockIdentifier.Hash != blockInfo.Hash {
+		return fmt.Errorf("%w: requested hash = %s, actual hash = %s", errBlockMismatch, *blockIdentifier.Hash, blockInfo.Hash)
+	}
+
+	return nil
+}
+
 // AccountCoins implements the /account/coins endpoint.
 func (service *accountService) AccountCoins(_ context.Context, _ *types.AccountCoinsRequest) (*types.AccountCoinsResponse, *types.Error) {
 	return nil, service.errFactory.newErr(ErrNotImplemented)
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/ElrondNetwork/elrond-proxy-go/data"
+	"github.com/ElrondNetwork/rosetta/server/resources"
 	"github.com/ElrondNetwork/rosetta/testscommon"
 	"github.com/coinbase/rosetta-sdk-go/server"
 	"github.com/coinbase/rosetta-sdk-go/types"
@@ -41,8 +42,205 @@ func TestAccountService_AccountBalance(t *testing.T) {
 	require.Equal(t, "abba", response.BlockIdentifier.Hash)
 }
 
+func TestAccountService_AccountBalanceWithCustomCurrencies(t *testing.T) {
+	networkProvider := testscommon.NewNetworkProviderMock()
+	networkProvider.MockCustomCurrencies = []resources.CustomCurrency{
+		{Identifier: "ROSETTA-3a2edf", Symbol: "ROSETTA", Decimals: 2},
+		{Identifier: "ROSETTA-057ab4", Symbol: "ROSETTA-057ab4", Decimals: 6},
+		{Identifier: "FUTURE-abcdef", Symbol: "FUTURE", Decimals: 0, ActivationNonce: 43},
+	}
+	networkProvider.MockLatestBlockSummary.Nonce = 42
+
+	networkProvider.MockAccountsByAddress[testscommon.TestAddressAlice] = &data.Account{
+		Address: testscommon.TestAddressAlice,
+		Balance: "100",
+	}
+	networkProvider.MockAccountsESDTBalances[testscommon.TestAddressAlice] = map[string]string{
+		"ROSETTA-3a2edf": "500",
+		"UNKNOWN-abcdef": "700",
+		"FUTURE-abcdef":  "800",
+	}
+
+	service := NewAccountService(networkProvider)
+
+	t.Run("with no specified currency (all supported currencies)", func(t *testing.T) {
+		response, err := getAccount(service, testscommon.TestAddressAlice)
+		require.Nil(t, err)
+		require.Len(t, response.Balances, 2)
+		require.Equal(t, "100", response.Balances[0].Value)
+		require.Equal(t, "XeGLD", response.Balances[0].Currency.Symbol)
+		require.Equal(t, "500", response.Balances[1].Value)
+		require.Equal(t, &types.Currency{Symbol: "ROSETTA", Decimals: 2}, response.Balances[1].Currency)
+	})
+
+	t.Run("with specified currencies", func(t *testing.T) {
+		response, err := getAccountWithCurrencies(service, testscommon.TestAddressAlice, []*types.Currency{
+			{Symbol: "ROSETTA-057ab4", Decimals: 6},
+			{Symbol: "XeGLD", Decimals: 18},
+			{Symbol: "ROSETTA", Decimals: 2},
+		})
+		require.Nil(t, err)
+		require.Len(t, response.Balances, 3)
+		require.Equal(t, "0", response.Balances[0].Value)
+		require.Equal(t, "ROSETTA-057ab4", response.Balances[0].Currency.Symbol)
+		require.Equal(t, "100", response.Balances[1].Value)
+		require.Equal(t, "500", response.Balances[2].Value)
+	})
+
+	t.Run("with unsupported currency", func(t *testing.T) {
+		response, err := getAccountWithCurrencies(service, testscommon.TestAddressAlice, []*types.Currency{
+			{Symbol: "UNKNOWN-abcdef", Decimals: 0},
+		})
+		require.Equal(t, ErrUnsupportedCurrency, errCode(err.Code))
+		require.Nil(t, response)
+	})
+
+	t.Run("with currency not yet active", func(t *testing.T) {
+		response, err := getAccountWithCurrencies(service, testscommon.TestAddressAlice, []*types.Currency{
+			{Symbol: "FUTURE", Decimals: 0},
+		})
+		require.Equal(t, ErrUnsupportedCurrency, errCode(err.Code))
+		require.Nil(t, response)
+	})
+}
+
+func TestAccountService_AccountBalanceWithCustomCurrenciesQueryOptions(t *testing.T) {
+	networkProvider := testscommon.NewNetworkProviderMock()
+	networkProvider.MockHistoricalBalanceLookup = true
+	networkProvider.MockCustomCurrencies = []resources.CustomCurrency{{Identifier: "ROSETTA-3a2edf", Symbol: "ROSETTA", Decimals: 2}}
+	networkProvider.MockLatestBlockSummary.Nonce = 42
+	networkProvider.MockBlocksByNonce[7] = &data.Block{Nonce: 7, Hash: "0007"}
+	networkProvider.MockBlocksByHash["0007"] = &data.Block{Nonce: 7, Hash: "0007"}
+	networkProvider.MockAccountsByAddress[testscommon.TestAddressAlice] = &data.Account{Address: testscommon.TestAddressAlice, Balance: "100"}
+
+	var esdtQueryOptions resources.AccountQueryOptions
+	esdtBlockInfo := data.BlockInfo{}
+	networkProvider.GetAccountESDTBalanceCalled = func(address string, tokenIdentifier string, options resources.AccountQueryOptions) (*resources.AccountESDTBalance, error) {
+		esdtQueryOptions = options
+		return &resources.AccountESDTBalance{Balance: "500", BlockInfo: esdtBlockInfo}, nil
+	}
+
+	service := NewAccountService(networkProvider)
+	currencies := []*types.Currency{{Symbol: "ROSETTA", Decimals: 2}}
+
+	t.Run("on final block (no historical query)", func(t *testing.T) {
+		esdtBlockInfo = data.BlockInfo{Nonce: 42, Hash: "latestHash"}
+
+		response, err := getAccountWithCurrencies(service, testscommon.TestAddressAlice, currencies)
+		require.Nil(t, err)
+		require.Equal(t, "500", response.Balances[0].Value)
+		require.Equal(t, resources.NewAccountQueryOptionsOnFinalBlock(), esdtQueryOptions)
+	})
+
+	t.Run("at block", func(t *testing.T) {
+		index := int64(7)
+		esdtBlockInfo = data.BlockInfo{Nonce: 7, Hash: "0007"}
+
+		response, err := service.AccountBalance(context.Background(), &types.AccountBalanceRequest{
+			AccountIdentifier: &types.AccountIdentifier{Address: testscommon.TestAddressAlice},
+			BlockIdentifier:   &types.PartialBlockIdentifier{Index: &index},
+			Currencies:        currencies,
+		})
+		require.Nil(t, err)
+		require.Equal(t, "500", response.Balances[0].Value)
+		require.Equal(t, resources.NewAccountQueryOptionsWithBlockNonce(7), esdtQueryOptions)
+	})
+
+	t.Run("at block, with mismatching block of the custom currency balance", func(t *testing.T) {
+		index := int64(7)
+		esdtBlockInfo = data.BlockInfo{Nonce: 8, Hash: "0008"}
+
+		response, err := service.AccountBalance(context.Background(), &types.AccountBalanceRequest{
+			AccountIdentifier: &types.AccountIdentifier{Address: testscommon.TestAddressAlice},
+			BlockIdentifier:   &types.PartialBlockIdentifier{Index: &index},
+			Currencies:        currencies,
+		})
+		require.Equal(t, ErrUnableToGetAccount, errCode(err.Code))
+		require.Nil(t, response)
+	})
+}
+
+func TestAccountService_AccountBalanceAtBlock(t *testing.T) {
+	networkProvider := testscommon.NewNetworkProviderMock()
+	networkProvider.MockHistoricalBalanceLookup = true
+	networkProvider.MockAccountsByAddress[testscommon.TestAddressAlice] = &data.Account{
+		Address: testscommon.TestAddressAlice,
+		Balance: "100",
+	}
+
+	block := &data.Block{Nonce: 7, Hash: "0007"}
+	networkProvider.MockBlocksByNonce[7] = block
+	networkProvider.MockBlocksByHash["0007"] = block
+
+	service := NewAccountService(networkProvider)
+
+	getAccountAtBlock := func(blockIdentifier *types.PartialBlockIdentifier) (*types.AccountBalanceResponse, *types.Error) {
+		return service.AccountBalance(context.Background(), &types.AccountBalanceRequest{
+			AccountIdentifier: &types.AccountIdentifier{Address: testscommon.TestAddressAlice},
+			BlockIdentifier:   blockIdentifier,
+		})
+	}
+
+	index := int64(7)
+	otherIndex := int64(8)
+	hash := "0007"
+
+	t.Run("by index", func(t *testing.T) {
+		response, err := getAccountAtBlock(&types.PartialBlockIdentifier{Index: &index})
+		require.Nil(t, err)
+		require.Equal(t, &types.BlockIdentifier{Index: 7, Hash: "0007"}, response.BlockIdentifier)
+	})
+
+	t.Run("by hash", func(t *testing.T) {
+		response, err := getAccountAtBlock(&types.PartialBlockIdentifier{Hash: &hash})
+		require.Nil(t, err)
+		require.Equal(t, &types.BlockIdentifier{Index: 7, Hash: "0007"}, response.BlockIdentifier)
+	})
+
+	t.Run("by index and hash", func(t *testing.T) {
+		response, err := getAccountAtBlock(&types.PartialBlockIdentifier{Index: &index, Hash: &hash})
+		require.Nil(t, err)
+		require.Equal(t, &types.BlockIdentifier{Index: 7, Hash: "0007"}, response.BlockIdentifier)
+	})
+
+	t.Run("with index and hash not matching", func(t *testing.T) {
+		response, err := getAccountAtBlock(&types.PartialBlockIdentifier{Index: &otherIndex, Hash: &hash})
+		require.Equal(t, ErrUnableToGetAccount, errCode(err.Code))
+		require.Nil(t, response)
+	})
+
+	t.Run("with negative index", func(t *testing.T) {
+		negativeIndex := int64(-1)
+		response, err := getAccountAtBlock(&types.PartialBlockIdentifier{Index: &negativeIndex})
+		require.Equal(t, ErrInvalidInputParam, errCode(err.Code))
+		require.Nil(t, response)
+	})
+
+	t.Run("with historical lookup not enabled", func(t *testing.T) {
+		networkProvider.MockHistoricalBalanceLookup = false
+		defer func() {
+			networkProvider.MockHistoricalBalanceLookup = true
+		}()
+
+		response, err := getAccountAtBlock(&types.PartialBlockIdentifier{Index: &index})
+		require.Equal(t, ErrInvalidInputParam, errCode(err.Code))
+		require.Nil(t, response)
+
+		response, err = getAccountAtBlock(nil)
+		require.Nil(t, err)
+		require.Equal(t, "100", response.Balances[0].Value)
+	})
+}
+
 func getAccount(service server.AccountAPIServicer, address string) (*types.AccountBalanceResponse, *types.Error) {
 	return service.AccountBalance(context.Background(), &types.AccountBalanceRequest{
 		AccountIdentifier: &types.AccountIdentifier{Address: address},
 	})
 }
+
+func getAccountWithCurrencies(service server.AccountAPIServicer, address string, currencies []*types.Currency) (*types.AccountBalanceResponse, *types.Error) {
+	return service.AccountBalance(context.Background(), &types.AccountBalanceRequest{
+		AccountIdentifier: &types.AccountIdentifier{Address: address},
+		Currencies:        currencies,
+	})
+}
@@ -2,8 +2,12 @@ package services
 
 import (
 	"context"
+	"errors"
 	"sync"
 
+	"github.com/ElrondNetwork/elrond-go-core/core"
+	"github.com/ElrondNetwork/elrond-go/storage"
+	"github.com/ElrondNetwork/elrond-go/storage/lrucache"
 	"github.com/ElrondNetwork/elrond-proxy-go/data"
 	"github.com/ElrondNetwork/rosetta/server/resources"
 	"github.com/coinbase/rosetta-sdk-go/server"
@@ -18,17 +22,25 @@ type blockService struct {
 
 	genesisBlock      *types.BlockResponse
 	genesisBlockMutex sync.RWMutex
+
+	// blocksCache holds the recently fetched blocks (above the "max inlined transactions" threshold, or requested by /block/transaction),
+	// as returned by the observer (not transformed), keyed by block hash. It is bounded by the (estimated) size of the blocks.
+	blocksCache storage.Cacher
 }
 
 // NewBlockService will create a new instance of blockService
 func NewBlockService(provider NetworkProvider) server.BlockAPIServicer {
 	extension := newNetworkProviderExtension(provider)
 
+	// The capacities are positive constants, thus the creation of the cache cannot fail.
+	blocksCache, _ := lrucache.NewCacheWithSizeInBytes(blocksCacheMaxNumBlocks, blocksCacheMaxSizeInBytes)
+
 	return &blockService{
 		provider:       provider,
 		extension:      extension,
 		errFactory:     newErrFactory(),
 		txsTransformer: newTransactionsTransformer(provider),
+		blocksCache:    blocksCache,
 	}
 }
 
@@ -195,33 +207,191 @@ func (service *blockService) convertToRosettaBlock(block *data.Block) (*types.Bl
 		parentBlockIdentifier = service.extension.getGenesisBlockIdentifier()
 	}
 
-	transactions, err := service.txsTransformer.transformTxsFromBlock(block)
+	transactions, otherTransactions, err := service.transformTxsFromBlock(block)
 	if err != nil {
 		return nil, err
 	}
 
+	metadata := objectsMap{
+		"shard":  block.Shard,
+		"epoch":  block.Epoch,
+		"round":  block.Round,
+		"status": block.Status,
+	}
+
+	if block.Shard == core.MetachainShardId {
+		addMetablockMetadata(block, metadata)
+	}
+
 	response := &types.BlockResponse{
+		OtherTransactions: otherTransactions,
 		Block: &types.Block{
 			BlockIdentifier:       blockToIdentifier(block),
 			ParentBlockIdentifier: parentBlockIdentifier,
 			Timestamp:             timestampInMilliseconds(int64(block.Timestamp)),
 			Transactions:          transactions,
-			Metadata: objectsMap{
-				"shard":  block.Shard,
-				"epoch":  block.Epoch,
-				"round":  block.Round,
-				"status": block.Status,
-			},
+			Metadata:              metadata,
 		},
 	}
 
 	return response, nil
 }
 
-// BlockTransaction is not implemented, since all transactions are returned by /block
+// addMetablockMetadata adds the shard blocks notarized by a metablock and, for the first block of an epoch, the epoch-start data.
+func addMetablockMetadata(block *data.Block, metadata objectsMap) {
+	notarizedBlocks := make([]objectsMap, 0, len(block.NotarizedBlocks))
+
+	for _, notarizedBlock := range block.NotarizedBlocks {
+		notarizedBlocks = append(notarizedBlocks, objectsMap{
+			"shard": notarizedBlock.Shard,
+			"nonce": notarizedBlock.Nonce,
+			"round": notarizedBlock.Round,
+			"hash":  notarizedBlock.Hash,
+		})
+	}
+
+	metadata["notarizedBlocks"] = notarizedBlocks
+
+	if block.EpochStartInfo != nil {
+		metadata["epochStartInfo"] = block.EpochStartInfo
+	}
+}
+
+// transformTxsFromBlock returns the transactions of a block. For blocks above the configured threshold, the transactions aren't transformed:
+// only their identifiers are returned ("other transactions"), while the transactions themselves have to be fetched (and transformed, one by one)
+// through /block/transaction. In the meantime, the block is cached, so that it isn't fetched again for each of them.
+func (service *blockService) transformTxsFromBlock(block *data.Block) ([]*types.Transaction, []*types.TransactionIdentifier, error) {
+	maxInlinedTransactions := service.provider.GetMaxInlinedTransactions()
+	numTransactions := countTransactionsInBlock(block)
+
+	if maxInlinedTransactions == 0 || numTransactions <= maxInlinedTransactions {
+		transactions, err := service.txsTransformer.transformTxsFromBlock(block)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return transactions, nil, nil
+	}
+
+	log.Debug("blockService.transformTxsFromBlock(): block above threshold", "nonce", block.Nonce, "numTransactions", numTransactions)
+
+	otherTransactions, err := service.txsTransformer.extractTxIdentifiersFromBlock(block)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	service.cacheBlock(block)
+	return []*types.Transaction{}, otherTransactions, nil
+}
+
+func (service *blockService) cacheBlock(block *data.Block) {
+	service.blocksCache.Put([]byte(block.Hash), block, estimateSizeOfBlock(block))
+}
+
+// getBlockByHashUsingCache returns a block, either from the cache, or by fetching it (in which case, the block is cached)
+func (service *blockService) getBlockByHashUsingCache(blockHash string) (*data.Block, error) {
+	cached, ok := service.blocksCache.Get([]byte(blockHash))
+	if ok {
+		return cached.(*data.Block), nil
+	}
+
+	block, err := service.provider.GetBlockByHash(blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	service.cacheBlock(block)
+	return block, nil
+}
+
+func countTransactionsInBlock(block *data.Block) uint64 {
+	count := 0
+
+	for _, miniblock := range filterOutPeerMiniblocks(block.MiniBlocks) {
+		count += len(miniblock.Transactions) + len(miniblock.Receipts)
+	}
+
+	return uint64(count)
+}
+
+// estimateSizeOfBlock gives a rough estimation of the memory held by a block: a fixed amount for each transaction and receipt,
+// plus the variable-length fields (data, events).
+func estimateSizeOfBlock(block *data.Block) int {
+	size := 0
+
+	for _, miniblock := range block.MiniBlocks {
+		size += len(miniblock.Receipts) * estimatedFixedSizeOfTransaction
+
+		for _, tx := range miniblock.Transactions {
+			size += estimatedFixedSizeOfTransaction + len(tx.Data)
+
+			if tx.Logs == nil {
+				continue
+			}
+
+			for _, event := range tx.Logs.Events {
+				size += len(event.Address) + len(event.Identifier) + len(event.Data)
+
+				for _, topic := range event.Topics {
+					size += len(topic)
+				}
+			}
+		}
+	}
+
+	return size
+}
+
+// BlockTransaction implements the /block/transaction endpoint.
+// The transaction is looked up within its block, and it is transformed (alone) by the very same pipeline used by /block
+// (filters, handling of scheduled miniblocks etc.), thus its operations are identical to the ones returned by /block.
 func (service *blockService) BlockTransaction(
 	_ context.Context,
-	_ *types.BlockTransactionRequest,
+	request *types.BlockTransactionRequest,
 ) (*types.BlockTransactionResponse, *types.Error) {
-	return nil, service.errFactory.newErr(ErrNotImplemented)
+	blockIdentifier := request.BlockIdentifier
+	txHash := request.TransactionIdentifier.Hash
+
+	log.Trace("blockService.BlockTransaction()", "block", blockIdentifier.Hash, "tx", txHash)
+
+	if blockIdentifier.Hash == service.extension.getGenesisBlockIdentifier().Hash {
+		return service.getGenesisBlockTransaction(txHash)
+	}
+
+	block, err := service.getBlockByHashUsingCache(blockIdentifier.Hash)
+	if err != nil {
+		return nil, service.errFactory.newErrWithOriginal(ErrUnableToGetBlock, err)
+	}
+	if int64(block.Nonce) != blockIdentifier.Index {
+		return nil, service.errFactory.newErrWithOriginal(ErrUnableToGetBlock, errors.New("block index and hash do not match"))
+	}
+
+	rosettaTx, ok, err := service.txsTransformer.transformTxFromBlock(block, txHash)
+	if err != nil {
+		return nil, service.errFactory.newErrWithOriginal(ErrUnableToGetBlock, err)
+	}
+	if !ok {
+		return nil, service.errFactory.newErr(ErrTransactionIsNotInBlock)
+	}
+
+	return &types.BlockTransactionResponse{
+		Transaction: rosettaTx,
+	}, nil
+}
+
+func (service *blockService) getGenesisBlockTransaction(txHash string) (*types.BlockTransactionResponse, *types.Error) {
+	genesisBlock, errTyped := service.getGenesisBlock()
+	if errTyped != nil {
+		return nil, errTyped
+	}
+
+	for _, rosettaTx := range genesisBlock.Block.Transactions {
+		if rosettaTx.TransactionIdentifier.Hash == txHash {
+			return &types.BlockTransactionResponse{
+				Transaction: rosettaTx,
+			}, nil
+		}
+	}
+
+	return nil, service.errFactory.newErr(ErrTransactionIsNotInBlock)
 }
@@ -2,8 +2,10 @@ package services
 
 import (
 	"context"
+	"math/big"
 	"testing"
 
+	"github.com/ElrondNetwork/elrond-go-core/core"
 	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
 	"github.com/ElrondNetwork/elrond-proxy-go/data"
 	"github.com/ElrondNetwork/rosetta/testscommon"
@@ -120,6 +122,379 @@ func TestBlockService_BlockByIndex(t *testing.T) {
 	require.Equal(t, blockEight, blockResponse.Block)
 }
 
+func TestBlockService_BlockTransaction(t *testing.T) {
+	networkProvider := testscommon.NewNetworkProviderMock()
+	networkProvider.MockNumShards = 1
+	networkProvider.MockComputedReceiptHash = "cccc"
+
+	block := &data.Block{
+		Hash:          "0007",
+		Nonce:         7,
+		PrevBlockHash: "0006",
+		MiniBlocks: []*data.MiniBlock{
+			{
+				Transactions: []*data.FullTransaction{
+					{
+						Hash:             "aaaa",
+						Type:             string(transaction.TxTypeNormal),
+						Sender:           testscommon.TestAddressAlice,
+						Receiver:         testscommon.TestAddressBob,
+						Value:            "1",
+						InitiallyPaidFee: "50000000000000",
+					},
+					{
+						Hash:                    "bbbb",
+						Type:                    string(transaction.TxTypeUnsigned),
+						Sender:                  testscommon.TestAddressBob,
+						Receiver:                testscommon.TestAddressAlice,
+						Value:                   "0",
+						OriginalTransactionHash: "aaaa",
+					},
+				},
+				Receipts: []*transaction.ApiReceipt{
+					{
+						Value:   big.NewInt(10000),
+						SndAddr: testscommon.TestAddressAlice,
+						Data:    refundGasMessage,
+						TxHash:  "aaaa",
+					},
+				},
+			},
+		},
+	}
+
+	networkProvider.MockBlocksByNonce[7] = block
+	networkProvider.MockBlocksByHash["0007"] = block
+
+	service := NewBlockService(networkProvider)
+
+	blockResponse, err := getBlockByIndex(service, 7)
+	require.Nil(t, err)
+	require.Len(t, blockResponse.Block.Transactions, 2)
+
+	t.Run("transaction in block", func(t *testing.T) {
+		response, err := getBlockTransaction(service, 7, "0007", "aaaa")
+		require.Nil(t, err)
+		require.Equal(t, blockResponse.Block.Transactions[0], response.Transaction)
+	})
+
+	t.Run("refund receipt in block", func(t *testing.T) {
+		response, err := getBlockTransaction(service, 7, "0007", "cccc")
+		require.Nil(t, err)
+		require.Equal(t, blockResponse.Block.Transactions[1], response.Transaction)
+	})
+
+	t.Run("contract result with no operations", func(t *testing.T) {
+		_, err := getBlockTransaction(service, 7, "0007", "bbbb")
+		require.Equal(t, ErrTransactionIsNotInBlock, errCode(err.Code))
+	})
+
+	t.Run("transaction not in block", func(t *testing.T) {
+		_, err := getBlockTransaction(service, 7, "0007", "dddd")
+		require.Equal(t, ErrTransactionIsNotInBlock, errCode(err.Code))
+	})
+
+	t.Run("block index and hash do not match", func(t *testing.T) {
+		_, err := getBlockTransaction(service, 8, "0007", "aaaa")
+		require.Equal(t, ErrUnableToGetBlock, errCode(err.Code))
+	})
+
+	t.Run("unknown block", func(t *testing.T) {
+		_, err := getBlockTransaction(service, 9, "0009", "aaaa")
+		require.Equal(t, ErrUnableToGetBlock, errCode(err.Code))
+	})
+}
+
+func TestBlockService_BlockWithOtherTransactions(t *testing.T) {
+	networkProvider := testscommon.NewNetworkProviderMock()
+	networkProvider.MockNumShards = 1
+	networkProvider.MockComputedReceiptHash = "cccc"
+
+	block := &data.Block{
+		Hash:          "0007",
+		Nonce:         7,
+		PrevBlockHash: "0006",
+		MiniBlocks: []*data.MiniBlock{
+			{
+				Transactions: []*data.FullTransaction{
+					{
+						Hash:             "aaaa",
+						Type:             string(transaction.TxTypeNormal),
+						Sender:           testscommon.TestAddressAlice,
+						Receiver:         testscommon.TestAddressBob,
+						Value:            "1",
+						InitiallyPaidFee: "50000000000000",
+					},
+					{
+						Hash:             "bbbb",
+						Type:             string(transaction.TxTypeNormal),
+						Sender:           testscommon.TestAddressBob,
+						Receiver:         testscommon.TestAddressAlice,
+						Value:            "2",
+						InitiallyPaidFee: "50000000000000",
+					},
+				},
+				Receipts: []*transaction.ApiReceipt{
+					{
+						Value:   big.NewInt(10000),
+						SndAddr: testscommon.TestAddressAlice,
+						Data:    refundGasMessage,
+						TxHash:  "aaaa",
+					},
+				},
+			},
+		},
+	}
+
+	networkProvider.MockBlocksByNonce[7] = block
+	networkProvider.MockBlocksByHash["0007"] = block
+
+	service := NewBlockService(networkProvider)
+
+	// Below threshold
+	networkProvider.MockMaxInlinedTransactions = 3
+	blockResponse, err := getBlockByIndex(service, 7)
+	require.Nil(t, err)
+	require.Len(t, blockResponse.Block.Transactions, 3)
+	require.Nil(t, blockResponse.OtherTransactions)
+	inlinedTransactions := blockResponse.Block.Transactions
+
+	// Above threshold
+	networkProvider.MockMaxInlinedTransactions = 2
+	blockResponse, err = getBlockByIndex(service, 7)
+	require.Nil(t, err)
+	require.Len(t, blockResponse.Block.Transactions, 0)
+	require.Equal(t, []*types.TransactionIdentifier{
+		hashToTransactionIdentifier("aaaa"),
+		hashToTransactionIdentifier("bbbb"),
+		hashToTransactionIdentifier("cccc"),
+	}, blockResponse.OtherTransactions)
+
+	// The transactions are served from the cache (the block isn't fetched again)
+	delete(networkProvider.MockBlocksByHash, "0007")
+
+	for i, identifier := range blockResponse.OtherTransactions {
+		response, err := getBlockTransaction(service, 7, "0007", identifier.Hash)
+		require.Nil(t, err)
+		require.Equal(t, inlinedTransactions[i], response.Transaction)
+	}
+
+	_, err = getBlockTransaction(service, 7, "0007", "dddd")
+	require.Equal(t, ErrTransactionIsNotInBlock, errCode(err.Code))
+
+	_, err = getBlockTransaction(service, 8, "0007", "aaaa")
+	require.Equal(t, ErrUnableToGetBlock, errCode(err.Code))
+}
+
+func TestBlockService_BlocksCacheIsBoundedBySize(t *testing.T) {
+	// Only one block fits in the cache
+	defer func(previous int64) { blocksCacheMaxSizeInBytes = previous }(blocksCacheMaxSizeInBytes)
+	blocksCacheMaxSizeInBytes = 1
+
+	networkProvider := testscommon.NewNetworkProviderMock()
+	networkProvider.MockNumShards = 1
+
+	for nonce, hash := range map[uint64]string{7: "0007", 8: "0008"} {
+		networkProvider.MockBlocksByHash[hash] = &data.Block{
+			Hash:  hash,
+			Nonce: nonce,
+			MiniBlocks: []*data.MiniBlock{
+				{
+					Transactions: []*data.FullTransaction{
+						{
+							Hash:             "aaaa",
+							Type:             string(transaction.TxTypeNormal),
+							Sender:           testscommon.TestAddressAlice,
+							Receiver:         testscommon.TestAddressBob,
+							Value:            "1",
+							InitiallyPaidFee: "50000000000000",
+						},
+					},
+				},
+			},
+		}
+	}
+
+	service := NewBlockService(networkProvider)
+
+	_, err := getBlockTransaction(service, 7, "0007", "aaaa")
+	require.Nil(t, err)
+	_, err = getBlockTransaction(service, 8, "0008", "aaaa")
+	require.Nil(t, err)
+
+	delete(networkProvider.MockBlocksByHash, "0007")
+	delete(networkProvider.MockBlocksByHash, "0008")
+
+	// The most recent block is still cached, while the other one has been evicted
+	_, err = getBlockTransaction(service, 8, "0008", "aaaa")
+	require.Nil(t, err)
+	_, err = getBlockTransaction(service, 7, "0007", "aaaa")
+	require.Equal(t, ErrUnableToGetBlock, errCode(err.Code))
+}
+func TestBlockService_BlockTransactionIsIdenticalToTheOneInBlock(t *testing.T) {
+	networkProvider := testscommon.NewNetworkProviderMock()
+	networkProvider.MockNumShards = 1
+
+	// Alice calls a contract, which sends 1000 to Bob twice: once by means of a contract result (which holds an event, as well),
+	// and once within an intra-shard async call (event on the transaction only). The two events are identical.
+	eventTransferToBob := &transaction.Events{
+		Address:    testscommon.TestAddressOfContract,
+		Identifier: transactionEventTransferValueOnly,
+		Topics:     [][]byte{testscommon.TestPubkeyOfContract, testscommon.TestPubKeyBob, big.NewInt(1000).Bytes()},
+	}
+
+	block := &data.Block{
+		Hash:          "0007",
+		Nonce:         7,
+		PrevBlockHash: "0006",
+		MiniBlocks: []*data.MiniBlock{
+			{
+				Transactions: []*data.FullTransaction{
+					{
+						Hash:             "aaaa",
+						Type:             string(transaction.TxTypeNormal),
+						Sender:           testscommon.TestAddressAlice,
+						Receiver:         testscommon.TestAddressOfContract,
+						Value:            "0",
+						Data:             []byte("doSomething"),
+						InitiallyPaidFee: "50000000000000",
+						Logs:             &transaction.ApiLogs{Events: []*transaction.Events{eventTransferToBob}},
+					},
+					{
+						Hash:                    "bbbb",
+						Type:                    string(transaction.TxTypeUnsigned),
+						Sender:                  testscommon.TestAddressOfContract,
+						Receiver:                testscommon.TestAddressBob,
+						Value:                   "1000",
+						OriginalTransactionHash: "aaaa",
+						Logs:                    &transaction.ApiLogs{Events: []*transaction.Events{eventTransferToBob}},
+					},
+				},
+			},
+		},
+	}
+
+	networkProvider.MockBlocksByNonce[7] = block
+	networkProvider.MockBlocksByHash["0007"] = block
+
+	service := NewBlockService(networkProvider)
+
+	blockResponse, err := getBlockByIndex(service, 7)
+	require.Nil(t, err)
+	require.Len(t, blockResponse.Block.Transactions, 2)
+
+	for _, rosettaTx := range blockResponse.Block.Transactions {
+		response, err := getBlockTransaction(service, 7, "0007", rosettaTx.TransactionIdentifier.Hash)
+		require.Nil(t, err)
+		require.Equal(t, rosettaTx, response.Transaction)
+	}
+}
+
+func TestBlockService_Metablock(t *testing.T) {
+	stakingContract := "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqplllst77y4l"
+
+	networkProvider := testscommon.NewNetworkProviderMock()
+	networkProvider.MockNumShards = 3
+	networkProvider.MockObservedActualShard = core.MetachainShardId
+	networkProvider.MockTrackedContracts[stakingContract] = struct{}{}
+	extension := newNetworkProviderExtension(networkProvider)
+
+	epochStartInfo := &data.EpochStartInfo{
+		TotalSupply:       "20000000000000000000000000",
+		TotalToDistribute: "1000",
+		NodePrice:         "2500000000000000000000",
+	}
+
+	networkProvider.MockBlocksByNonce[7] = &data.Block{
+		Hash:          "0007",
+		Nonce:         7,
+		Timestamp:     1,
+		PrevBlockHash: "0006",
+		Shard:         core.MetachainShardId,
+		Epoch:         2,
+		Round:         42,
+		Status:        "on-chain",
+		NotarizedBlocks: []*data.NotarizedBlock{
+			{Shard: 0, Nonce: 5, Round: 41, Hash: "aa05"},
+			{Shard: 1, Nonce: 6, Round: 41, Hash: "bb06"},
+		},
+		EpochStartInfo: epochStartInfo,
+		MiniBlocks: []*data.MiniBlock{
+			{
+				Type: "TxBlock",
+				Transactions: []*data.FullTransaction{
+					{
+						Hash:             "aaaa",
+						Type:             string(transaction.TxTypeNormal),
+						Sender:           testscommon.TestAddressAlice,
+						Receiver:         stakingContract,
+						Value:            "2500",
+						InitiallyPaidFee: "50000000000000",
+					},
+				},
+			},
+			{
+				Type: "RewardsBlock",
+				Transactions: []*data.FullTransaction{
+					{
+						Hash:     "bbbb",
+						Type:     string(transaction.TxTypeReward),
+						Receiver: testscommon.TestAddressBob,
+						Value:    "1000",
+					},
+				},
+			},
+			{
+				Type: "PeerBlock",
+				Transactions: []*data.FullTransaction{
+					{
+						Hash:     "cccc",
+						Type:     string(transaction.TxTypeNormal),
+						Sender:   testscommon.TestAddressAlice,
+						Receiver: stakingContract,
+						Value:    "1",
+					},
+				},
+			},
+		},
+	}
+
+	service := NewBlockService(networkProvider)
+
+	blockResponse, err := getBlockByIndex(service, 7)
+	require.Nil(t, err)
+	require.Equal(t, &types.Block{
+		BlockIdentifier:       &types.BlockIdentifier{Index: 7, Hash: "0007"},
+		ParentBlockIdentifier: &types.BlockIdentifier{Index: 6, Hash: "0006"},
+		Timestamp:             1000,
+		Transactions: []*types.Transaction{
+			{
+				TransactionIdentifier: hashToTransactionIdentifier("aaaa"),
+				Operations: []*types.Operation{
+					{
+						OperationIdentifier: indexToOperationIdentifier(0),
+						Type:                opTransfer,
+						Account:             addressToAccountIdentifier(stakingContract),
+						Amount:              extension.valueToNativeAmount("2500"),
+						Status:              &opStatusSuccess,
+					},
+				},
+			},
+		},
+		Metadata: objectsMap{
+			"epoch":  uint32(2),
+			"round":  uint64(42),
+			"shard":  core.MetachainShardId,
+			"status": "on-chain",
+			"notarizedBlocks": []objectsMap{
+				{"shard": uint32(0), "nonce": uint64(5), "round": uint64(41), "hash": "aa05"},
+				{"shard": uint32(1), "nonce": uint64(6), "round": uint64(41), "hash": "bb06"},
+			},
+			"epochStartInfo": epochStartInfo,
+		},
+	}, blockResponse.Block)
+}
+
 func getBlockByIndex(service server.BlockAPIServicer, index int64) (*types.BlockResponse, *types.Error) {
 	return service.Block(context.Background(), &types.BlockRequest{
 		NetworkIdentifier: nil,
@@ -129,3 +504,10 @@ func getBlockByIndex(service server.BlockAPIServicer, index int64) (*types.Block
 		},
 	})
 }
+
+func getBlockTransaction(service server.BlockAPIServicer, index int64, hash string, txHash string) (*types.BlockTransactionResponse, *types.Error) {
+	return service.BlockTransaction(context.Background(), &types.BlockTransactionRequest{
+		BlockIdentifier:       &types.BlockIdentifier{Index: index, Hash: hash},
+		TransactionIdentifier: &types.TransactionIdentifier{Hash: txHash},
+	})
+}
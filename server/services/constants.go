@@ -4,16 +4,39 @@ import "encoding/hex"
 
 var (
 	transactionVersion                           = 1
+	transactionVersionWithOptions                = 2
+	transactionOptionSignedByHash                = 1
 	transactionProcessingTypeRelayed             = "RelayedTx"
 	transactionProcessingTypeBuiltInFunctionCall = "BuiltInFunctionCall"
 	transactionProcessingTypeMoveBalance         = "MoveBalance"
 	builtInFunctionClaimDeveloperRewards         = "ClaimDeveloperRewards"
+	builtInFunctionESDTTransfer                  = "ESDTTransfer"
+	builtInFunctionESDTNFTTransfer               = "ESDTNFTTransfer"
+	builtInFunctionMultiESDTNFTTransfer          = "MultiESDTNFTTransfer"
+	builtInFunctionRelayedTx                     = "relayedTx"
+	builtInFunctionRelayedTxV2                   = "relayedTxV2"
+	gasCostOfBuiltInFunctionESDTTransfer         = uint64(200000)
 	refundGasMessage                             = "refundedGas"
 	sendingValueToNonPayableContractDataPrefix   = "@" + hex.EncodeToString([]byte("sending value to non payable contract"))
 	emptyHash                                    = "0000000000000000000000000000000000000000000000000000000000000000"
 )
 
 var (
-	transactionEventSignalError       = "signalError"
-	transactionEventTransferValueOnly = "transferValueOnly"
+	blocksCacheMaxNumBlocks         = 64
+	blocksCacheMaxSizeInBytes       = int64(256 * 1024 * 1024)
+	estimatedFixedSizeOfTransaction = 1024
+)
+
+var (
+	syncStageSynced  = "synced"
+	syncStageSyncing = "syncing"
+)
+
+var (
+	transactionEventSignalError          = "signalError"
+	transactionEventSCDeploy             = "SCDeploy"
+	transactionEventTransferValueOnly    = "transferValueOnly"
+	transactionEventESDTTransfer         = "ESDTTransfer"
+	transactionEventESDTNFTTransfer      = "ESDTNFTTransfer"
+	transactionEventMultiESDTNFTTransfer = "MultiESDTNFTTransfer"
 )
@@ -5,9 +5,10 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"errors"
-	"fmt"
+	"math/big"
 
 	"github.com/ElrondNetwork/elrond-proxy-go/data"
+	"github.com/ElrondNetwork/rosetta/server/resources"
 	"github.com/coinbase/rosetta-sdk-go/server"
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
@@ -65,6 +66,12 @@ func (service *constructionService) ConstructionPreprocess(
 	if request.Metadata["data"] != nil {
 		options["data"] = request.Metadata["data"]
 	}
+	if request.Metadata["relayer"] != nil {
+		options["relayer"] = request.Metadata["relayer"]
+	}
+	if request.Metadata["signByHash"] != nil {
+		options["signByHash"] = request.Metadata["signByHash"]
+	}
 
 	return &types.ConstructionPreprocessResponse{
 		Options: options,
@@ -80,11 +87,15 @@ func (service *constructionService) checkOperationsAndMeta(ops []*types.Operatio
 		if !checkOperationsType(op) {
 			return service.errFactory.newErrWithOriginal(ErrConstructionCheck, errors.New("unsupported operation type"))
 		}
-		if op.Amount.Currency.Symbol != service.extension.getNativeCurrency().Symbol {
-			return service.errFactory.newErrWithOriginal(ErrConstructionCheck, errors.New("unsupported currency symbol"))
+		if !service.isCurrencySupportedForConstruction(op.Amount.Currency) {
+			return service.errFactory.newErr(ErrUnsupportedCurrency)
 		}
 	}
 
+	// Guarded transactions (co-signed by a guardian) cannot be constructed, since the pinned dependencies do not define the guardian fields.
+	if meta["guardian"] != nil || meta["guardianSignature"] != nil {
+		return service.errFactory.newErr(ErrGuardedTransactionsNotSupported)
+	}
 	if meta["gasLimit"] != nil {
 		if !checkValueIsOk(meta["gasLimit"]) {
 			return service.errFactory.newErrWithOriginal(ErrConstructionCheck, errors.New("invalid metadata gas limit"))
@@ -122,12 +133,21 @@ func (service *constructionService) getOptionsFromOperations(ops []*types.Operat
 	if len(ops) < 2 {
 		return nil, service.errFactory.newErrWithOriginal(ErrConstructionCheck, errors.New("invalid number of operations"))
 	}
+	currencySymbol := ops[1].Amount.Currency.Symbol
+	if ops[0].Amount.Currency.Symbol != currencySymbol {
+		return nil, service.errFactory.newErrWithOriginal(ErrConstructionCheck, errors.New("operations must have the same currency"))
+	}
+
 	options := make(objectsMap)
 	options["sender"] = ops[0].Account.Address
 	options["receiver"] = ops[1].Account.Address
 	options["type"] = ops[0].Type
 	options["value"] = ops[1].Amount.Value
 
+	if !service.extension.isNativeCurrencySymbol(currencySymbol) {
+		options["currencySymbol"] = currencySymbol
+	}
+
 	return options, nil
 }
 
@@ -151,7 +171,23 @@ func (service *constructionService) ConstructionMetadata(
 	}
 
 	networkConfig := service.provider.GetNetworkConfig()
-	suggestedFee, gasPrice, gasLimit, err := service.computeSuggestedFeeAndGas(txType, request.Options, networkConfig)
+	options, err := service.estimateGasLimitOfContractCall(request.Options, metadata, networkConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestedFee, gasPrice, gasLimit, err := service.computeSuggestedFeeAndGas(txType, options, networkConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, isRelayed := metadata["relayer"]; isRelayed {
+		gasLimitOfRelayingOverhead := computeGasLimitOfRelayingOverhead(metadata, networkConfig)
+		gasLimit += gasLimitOfRelayingOverhead
+		suggestedFee.Add(suggestedFee, big.NewInt(0).SetUint64(gasLimitOfRelayingOverhead*gasPrice))
+	}
+
+	suggestedFee, gasPrice, err = service.applyMaxFee(suggestedFee, gasPrice, gasLimit, request.Options, networkConfig.MinGasPrice)
 	if err != nil {
 		return nil, err
 	}
@@ -169,10 +205,6 @@ func (service *constructionService) ConstructionMetadata(
 
 func (service *constructionService) computeMetadata(options objectsMap) (objectsMap, *types.Error) {
 	metadata := make(objectsMap)
-	if dataField, ok := options["data"]; ok {
-		// convert string to byte array
-		metadata["data"] = []byte(fmt.Sprintf("%v", dataField))
-	}
 
 	var ok bool
 	if metadata["sender"], ok = options["sender"]; !ok {
@@ -185,9 +217,31 @@ func (service *constructionService) computeMetadata(options objectsMap) (objects
 		return nil, service.errFactory.newErrWithOriginal(ErrMalformedValue, errors.New("value missing"))
 	}
 
+	dataField, errTyped := service.computeDataFieldOfOptions(options)
+	if errTyped != nil {
+		return nil, errTyped
+	}
+	if dataField != nil {
+		metadata["data"] = dataField
+	}
+
+	_, isCustomCurrencyTransfer, errTyped := service.getCustomCurrencyOfOptions(options)
+	if errTyped != nil {
+		return nil, errTyped
+	}
+	if isCustomCurrencyTransfer {
+		// The amount of tokens is held in the data field.
+		metadata["value"] = "0"
+	}
+
 	metadata["chainID"] = service.provider.GetChainID()
 	metadata["version"] = transactionVersion
 
+	if isSigningByHashRequested(options) {
+		metadata["version"] = transactionVersionWithOptions
+		metadata["options"] = transactionOptionSignedByHash
+	}
+
 	senderAddressI, ok := options["sender"]
 	if !ok {
 		return nil, service.errFactory.newErrWithOriginal(ErrInvalidInputParam, errors.New("cannot find sender address"))
@@ -197,13 +251,18 @@ func (service *constructionService) computeMetadata(options objectsMap) (objects
 		return nil, service.errFactory.newErrWithOriginal(ErrMalformedValue, errors.New("sender address is invalid"))
 	}
 
-	account, err := service.provider.GetAccount(senderAddress)
+	account, err := service.provider.GetAccount(senderAddress, resources.NewAccountQueryOptionsOnFinalBlock())
 	if err != nil {
 		return nil, service.errFactory.newErrWithOriginal(ErrUnableToGetAccount, err)
 	}
 
 	metadata["nonce"] = account.Account.Nonce
 
+	errTyped = service.computeMetadataOfRelayer(options, metadata)
+	if errTyped != nil {
+		return nil, errTyped
+	}
+
 	return metadata, nil
 }
 
@@ -221,11 +280,20 @@ func (service *constructionService) ConstructionPayloads(
 		return nil, service.errFactory.newErrWithOriginal(ErrMalformedValue, err)
 	}
 
+	if _, isRelayed := request.Metadata["relayer"]; isRelayed {
+		return service.constructionPayloadsOfRelayedTx(request, tx)
+	}
+
 	txJson, err := json.Marshal(tx)
 	if err != nil {
 		return nil, service.errFactory.newErrWithOriginal(ErrMalformedValue, err)
 	}
 
+	signingPayload, err := service.computeSigningPayload(tx)
+	if err != nil {
+		return nil, service.errFactory.newErrWithOriginal(ErrMalformedValue, err)
+	}
+
 	signer := request.Operations[0].Account.Address
 
 	return &types.ConstructionPayloadsResponse{
@@ -234,7 +302,7 @@ func (service *constructionService) ConstructionPayloads(
 			{
 				AccountIdentifier: addressToAccountIdentifier(signer),
 				SignatureType:     types.Ed25519,
-				Bytes:             txJson,
+				Bytes:             signingPayload,
 			},
 		},
 	}, nil
@@ -248,7 +316,24 @@ func (service *constructionService) ConstructionParse(
 ) (*types.ConstructionParseResponse, *types.Error) {
 	tx, err := getTxFromRequest(request.Transaction)
 	if err != nil {
-		return nil, service.errFactory.newErrWithOriginal(ErrMalformedValue, err)
+		return nil, service.errFactory.newErrOfTxFromRequest(err)
+	}
+
+	if innerTx, innerSignature, isRelayed := service.parseRelayedTxV2(tx); isRelayed {
+		var signers []*types.AccountIdentifier
+		if request.Signed {
+			signers = getSignersOfRelayedTx(tx, innerTx, innerSignature)
+		}
+
+		operations, errTyped := service.createOperationsFromPreparedTx(innerTx)
+		if errTyped != nil {
+			return nil, errTyped
+		}
+
+		return &types.ConstructionParseResponse{
+			Operations:               operations,
+			AccountIdentifierSigners: signers,
+		}, nil
 	}
 
 	var signers []*types.AccountIdentifier
@@ -260,13 +345,29 @@ func (service *constructionService) ConstructionParse(
 		}
 	}
 
+	operations, errTyped := service.createOperationsFromPreparedTx(tx)
+	if errTyped != nil {
+		return nil, errTyped
+	}
+
 	return &types.ConstructionParseResponse{
-		Operations:               service.createOperationsFromPreparedTx(tx),
+		Operations:               operations,
 		AccountIdentifierSigners: signers,
 	}, nil
 }
 
-func (service *constructionService) createOperationsFromPreparedTx(tx *data.Transaction) []*types.Operation {
+func (service *constructionService) createOperationsFromPreparedTx(tx *data.Transaction) ([]*types.Operation, *types.Error) {
+	tokenIdentifier, tokenValue, isESDTTransfer := parseESDTTransferData(tx.Data)
+	if isESDTTransfer {
+		// A transfer of an unsupported (or not yet active) token must not be presented as a native transfer.
+		currency, isSupported := service.provider.GetCustomCurrencyByIdentifier(tokenIdentifier)
+		if !isSupported || !service.isCustomCurrencyActiveForConstruction(currency) {
+			return nil, service.errFactory.newErr(ErrUnsupportedCurrency)
+		}
+
+		return service.createOperationsOfCustomCurrencyTransfer(tx, tokenValue, currency), nil
+	}
+
 	operations := []*types.Operation{
 		{
 			Type:    opTransfer,
@@ -282,6 +383,29 @@ func (service *constructionService) createOperationsFromPreparedTx(tx *data.Tran
 
 	indexOperations(operations)
 
+	return operations, nil
+}
+
+func (service *constructionService) createOperationsOfCustomCurrencyTransfer(
+	tx *data.Transaction,
+	value string,
+	currency resources.CustomCurrency,
+) []*types.Operation {
+	operations := []*types.Operation{
+		{
+			Type:    opTransfer,
+			Account: addressToAccountIdentifier(tx.Sender),
+			Amount:  service.extension.valueToCustomAmount("-"+value, currency),
+		},
+		{
+			Type:    opTransfer,
+			Account: addressToAccountIdentifier(tx.Receiver),
+			Amount:  service.extension.valueToCustomAmount(value, currency),
+		},
+	}
+
+	indexOperations(operations)
+
 	return operations
 }
 
@@ -301,6 +425,13 @@ func createTransaction(request *types.ConstructionPayloadsRequest) (*data.Transa
 	return tx, nil
 }
 
+// guardianFieldsOfTransaction holds the guardian fields of a transaction, which are not defined by the pinned dependencies
+// (and would be silently dropped when decoding into a data.Transaction).
+type guardianFieldsOfTransaction struct {
+	Guardian          string `json:"guardian"`
+	GuardianSignature string `json:"guardianSignature"`
+}
+
 func getTxFromRequest(txString string) (*data.Transaction, error) {
 	txBytes := []byte(txString)
 
@@ -310,6 +441,15 @@ func getTxFromRequest(txString string) (*data.Transaction, error) {
 		return nil, err
 	}
 
+	var guardianFields guardianFieldsOfTransaction
+	err = json.Unmarshal(txBytes, &guardianFields)
+	if err != nil {
+		return nil, err
+	}
+	if len(guardianFields.Guardian) > 0 || len(guardianFields.GuardianSignature) > 0 {
+		return nil, errGuardedTransaction
+	}
+
 	return &tx, nil
 }
 
@@ -320,13 +460,22 @@ func (service *constructionService) ConstructionCombine(
 ) (*types.ConstructionCombineResponse, *types.Error) {
 	tx, err := getTxFromRequest(request.UnsignedTransaction)
 	if err != nil {
-		return nil, service.errFactory.newErrWithOriginal(ErrMalformedValue, err)
+		return nil, service.errFactory.newErrOfTxFromRequest(err)
+	}
+
+	if innerTx, innerSignature, isRelayed := service.parseRelayedTxV2(tx); isRelayed {
+		return service.combineRelayedTx(tx, innerTx, innerSignature, request.Signatures)
 	}
 
 	if len(request.Signatures) != 1 {
 		return nil, service.errFactory.newErr(ErrInvalidInputParam)
 	}
 
+	errTyped := service.verifySignature(tx, request.Signatures[0])
+	if errTyped != nil {
+		return nil, errTyped
+	}
+
 	tx.Signature = hex.EncodeToString(request.Signatures[0].Bytes)
 
 	signedTxBytes, err := json.Marshal(tx)
@@ -364,7 +513,7 @@ func (service *constructionService) ConstructionHash(
 ) (*types.TransactionIdentifierResponse, *types.Error) {
 	elrondTx, err := getTxFromRequest(request.SignedTransaction)
 	if err != nil {
-		return nil, service.errFactory.newErrWithOriginal(ErrMalformedValue, err)
+		return nil, service.errFactory.newErrOfTxFromRequest(err)
 	}
 
 	txHash, err := service.provider.ComputeTransactionHash(elrondTx)
@@ -390,7 +539,7 @@ func (service *constructionService) ConstructionSubmit(
 
 	elrondTx, err := getTxFromRequest(request.SignedTransaction)
 	if err != nil {
-		return nil, service.errFactory.newErrWithOriginal(ErrMalformedValue, err)
+		return nil, service.errFactory.newErrOfTxFromRequest(err)
 	}
 
 	txHash, err := service.provider.SendTransaction(elrondTx)
@@ -1,9 +1,12 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 
+	"github.com/ElrondNetwork/elrond-go-core/core"
+	"github.com/ElrondNetwork/elrond-proxy-go/data"
 	"github.com/ElrondNetwork/rosetta/server/resources"
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
@@ -83,16 +86,119 @@ func (service *constructionService) adjustTxFeeWithFeeMultiplier(
 	return result, gasPrice
 }
 
+// applyMaxFee makes sure that the transaction cannot cost more than the max fee (if provided). The fee is bounded by gasPrice * gasLimit
+// (the suggested fee, adjusted by the fee multiplier, might be lower, due to the minimum gas price). If the bound exceeds the max fee,
+// the gas price is capped, as long as it doesn't go below the minimum gas price. Otherwise, an error is returned.
+func (service *constructionService) applyMaxFee(
+	suggestedFee *big.Int, gasPrice uint64, gasLimit uint64, options objectsMap, minGasPrice uint64,
+) (*big.Int, uint64, *types.Error) {
+	maxFeeI, ok := options["maxFee"]
+	if !ok {
+		return suggestedFee, gasPrice, nil
+	}
+
+	maxFee, ok := big.NewInt(0).SetString(fmt.Sprintf("%v", maxFeeI), 10)
+	if !ok {
+		return nil, 0, service.errFactory.newErrWithOriginal(ErrInvalidInputParam, errors.New("invalid max fee"))
+	}
+
+	gasLimitBig := big.NewInt(0).SetUint64(gasLimit)
+	maxPossibleFee := big.NewInt(0).Mul(big.NewInt(0).SetUint64(gasPrice), gasLimitBig)
+	if maxPossibleFee.Cmp(maxFee) <= 0 {
+		return suggestedFee, gasPrice, nil
+	}
+
+	cappedGasPrice := big.NewInt(0).Div(maxFee, gasLimitBig)
+	if cappedGasPrice.Cmp(big.NewInt(0).SetUint64(minGasPrice)) < 0 {
+		return nil, 0, service.errFactory.newErrWithOriginal(ErrMaxFeeExceeded, fmt.Errorf("fee = %s, max fee = %s (gas limit = %d, min gas price = %d)", maxPossibleFee, maxFee, gasLimit, minGasPrice))
+	}
+
+	gasPrice = cappedGasPrice.Uint64()
+	suggestedFee = big.NewInt(0).Mul(cappedGasPrice, gasLimitBig)
+	return suggestedFee, gasPrice, nil
+}
+
+// estimateGasLimitOfContractCall estimates the gas limit of a smart contract call (a transaction with a data field, towards a contract)
+// by simulating the transaction (on the observer), since the gas needed for the execution cannot be computed offline.
+// A safety margin is added to the simulated cost. The estimated gas limit is then handled as if it was provided in the options.
+func (service *constructionService) estimateGasLimitOfContractCall(
+	options objectsMap, metadata objectsMap, networkConfig *resources.NetworkConfig,
+) (objectsMap, *types.Error) {
+	if _, ok := options["gasLimit"]; ok {
+		return options, nil
+	}
+
+	dataField, _ := metadata["data"].([]byte)
+	if len(dataField) == 0 {
+		return options, nil
+	}
+
+	receiver, _ := metadata["receiver"].(string)
+	receiverPubkey, err := service.provider.ConvertAddressToPubKey(receiver)
+	if err != nil {
+		return nil, service.errFactory.newErrWithOriginal(ErrMalformedValue, err)
+	}
+	if !core.IsSmartContractAddress(receiverPubkey) {
+		return options, nil
+	}
+
+	gasPrice := networkConfig.MinGasPrice
+	if gasPriceI, ok := options["gasPrice"]; ok {
+		gasPrice = getUint64Value(gasPriceI)
+	}
+
+	// The Node fills in the gas limit (and a dummy signature) when simulating the transaction.
+	tx := &data.Transaction{
+		Nonce:    getUint64Value(metadata["nonce"]),
+		Value:    fmt.Sprintf("%v", metadata["value"]),
+		Receiver: receiver,
+		Sender:   fmt.Sprintf("%v", metadata["sender"]),
+		GasPrice: gasPrice,
+		Data:     dataField,
+		ChainID:  service.provider.GetChainID(),
+		Version:  uint32(transactionVersion),
+	}
+
+	cost, err := service.provider.SimulateTransactionCost(tx)
+	if err != nil {
+		return nil, service.errFactory.newErrWithOriginal(ErrUnableToSimulateTransaction, err)
+	}
+	if cost.RetMessage != "" || cost.TxCost == 0 {
+		return nil, service.errFactory.newErrWithOriginal(ErrTransactionSimulationFailed, fmt.Errorf("simulation failed: %s", cost.RetMessage))
+	}
+
+	safetyMargin := cost.TxCost * service.provider.GetGasLimitSafetyMargin() / 100
+
+	optionsWithGasLimit := make(objectsMap, len(options)+1)
+	for key, value := range options {
+		optionsWithGasLimit[key] = value
+	}
+	optionsWithGasLimit["gasLimit"] = cost.TxCost + safetyMargin
+
+	return optionsWithGasLimit, nil
+}
+
 func (service *constructionService) estimateGasLimit(operationType string, networkConfig *resources.NetworkConfig, options objectsMap) (uint64, *types.Error) {
-	gasForDataField := uint64(0)
-	if dataFieldI, ok := options["data"]; ok {
-		dataField := fmt.Sprintf("%v", dataFieldI)
-		gasForDataField = networkConfig.GasPerDataByte * uint64(len(dataField))
+	dataField, err := service.computeDataFieldOfOptions(options)
+	if err != nil {
+		return 0, err
+	}
+
+	gasForDataField := networkConfig.GasPerDataByte * uint64(len(dataField))
+
+	_, isCustomCurrencyTransfer, err := service.getCustomCurrencyOfOptions(options)
+	if err != nil {
+		return 0, err
+	}
+
+	gasForBuiltInFunction := uint64(0)
+	if isCustomCurrencyTransfer {
+		gasForBuiltInFunction = gasCostOfBuiltInFunctionESDTTransfer
 	}
 
 	switch operationType {
 	case opTransfer:
-		return networkConfig.MinGasLimit + gasForDataField, nil
+		return networkConfig.MinGasLimit + gasForDataField + gasForBuiltInFunction, nil
 	default:
 		//  we do not support this yet other operation types, but we might support it in the future
 		return 0, service.errFactory.newErr(ErrNotImplemented)
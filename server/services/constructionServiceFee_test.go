@@ -170,3 +170,46 @@ func TestAdjustTxFeeWithFeeMultiplier_FeeMultiplierLessThanOne(t *testing.T) {
 	require.Equal(t, expectedFee, suggestedFeeResult.String())
 	require.Equal(t, minGasPrice, gasPriceResult)
 }
+
+func TestApplyMaxFee(t *testing.T) {
+	t.Parallel()
+
+	networkProvider := testscommon.NewNetworkProviderMock()
+	service := NewConstructionService(networkProvider).(*constructionService)
+
+	gasLimit := uint64(100)
+	minGasPrice := uint64(900)
+
+	// Max fee not provided
+	suggestedFee, gasPrice, err := service.applyMaxFee(big.NewInt(100000), 1000, gasLimit, make(objectsMap), minGasPrice)
+	require.Nil(t, err)
+	require.Equal(t, "100000", suggestedFee.String())
+	require.Equal(t, uint64(1000), gasPrice)
+
+	// Max fee not exceeded
+	suggestedFee, gasPrice, err = service.applyMaxFee(big.NewInt(100000), 1000, gasLimit, objectsMap{"maxFee": "100000"}, minGasPrice)
+	require.Nil(t, err)
+	require.Equal(t, "100000", suggestedFee.String())
+	require.Equal(t, uint64(1000), gasPrice)
+
+	// Max fee exceeded, gas price is capped
+	suggestedFee, gasPrice, err = service.applyMaxFee(big.NewInt(100000), 1000, gasLimit, objectsMap{"maxFee": "95050"}, minGasPrice)
+	require.Nil(t, err)
+	require.Equal(t, "95000", suggestedFee.String())
+	require.Equal(t, uint64(950), gasPrice)
+
+	// Fee multiplier less than one: the suggested fee is below the max fee, but the gas price (bounded by the minimum) is not
+	suggestedFee, gasPrice = service.adjustTxFeeWithFeeMultiplier(big.NewInt(100000), 1000, objectsMap{"feeMultiplier": 0.5}, minGasPrice)
+	require.Equal(t, "50000", suggestedFee.String())
+	require.Equal(t, minGasPrice, gasPrice)
+	_, _, err = service.applyMaxFee(suggestedFee, gasPrice, gasLimit, objectsMap{"maxFee": "60000"}, minGasPrice)
+	require.Equal(t, ErrMaxFeeExceeded, errCode(err.Code))
+
+	// Max fee too low
+	_, _, err = service.applyMaxFee(big.NewInt(100000), 1000, gasLimit, objectsMap{"maxFee": "89999"}, minGasPrice)
+	require.Equal(t, ErrMaxFeeExceeded, errCode(err.Code))
+
+	// Bad max fee
+	_, _, err = service.applyMaxFee(big.NewInt(100000), 1000, gasLimit, objectsMap{"maxFee": "foobar"}, minGasPrice)
+	require.Equal(t, ErrInvalidInputParam, errCode(err.Code))
+}
@@ -0,0 +1,312 @@
+package services
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ElrondNetwork/elrond-proxy-go/data"
+	"github.com/ElrondNetwork/rosetta/server/resources"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// Relayed transactions (v2) are constructed in two rounds, since the relayer signs over the signature of the inner transaction:
+//  1. /payloads (with "relayer" in metadata) returns the payload of the inner transaction (to be signed by the user),
+//     then /combine embeds the user's signature into the relayed transaction.
+//  2. /payloads (with "relayer" and "innerSignature" in metadata) returns both payloads: the one of the inner transaction (same as at step 1)
+//     and the one of the relayed transaction (to be signed by the relayer). Then, /combine accepts both signatures (the user's signature
+//     must be the embedded one - Ed25519 signatures are deterministic), or only the relayer's one.
+
+const (
+	pubkeyLength    = 32
+	signatureLength = 64
+)
+
+// getRelayerOfOptions returns the relayer (sponsor) of the transaction to be constructed, if any
+func (service *constructionService) getRelayerOfOptions(options objectsMap) (string, bool, *types.Error) {
+	relayerI, ok := options["relayer"]
+	if !ok {
+		return "", false, nil
+	}
+
+	relayer, ok := relayerI.(string)
+	if !ok {
+		return "", false, service.errFactory.newErrWithOriginal(ErrMalformedValue, errors.New("relayer address is invalid"))
+	}
+
+	return relayer, true, nil
+}
+
+// computeMetadataOfRelayer adds the relayer (and its nonce) to the metadata, if the transaction to be constructed is a relayed one
+func (service *constructionService) computeMetadataOfRelayer(options objectsMap, metadata objectsMap) *types.Error {
+	relayer, isRelayed, errTyped := service.getRelayerOfOptions(options)
+	if errTyped != nil || !isRelayed {
+		return errTyped
+	}
+
+	if relayer == metadata["sender"] {
+		return service.errFactory.newErrWithOriginal(ErrConstructionCheck, errors.New("relayer must be different from sender"))
+	}
+	if fmt.Sprintf("%v", metadata["value"]) != "0" {
+		return service.errFactory.newErrWithOriginal(ErrConstructionCheck, errors.New("relayed transactions cannot transfer native value"))
+	}
+
+	account, err := service.provider.GetAccount(relayer, resources.NewAccountQueryOptionsOnFinalBlock())
+	if err != nil {
+		return service.errFactory.newErrWithOriginal(ErrUnableToGetAccount, err)
+	}
+
+	metadata["relayer"] = relayer
+	metadata["relayerNonce"] = account.Account.Nonce
+
+	return nil
+}
+
+// computeGasLimitOfRelayingOverhead computes the gas needed by the relayed transaction itself (on top of the gas needed by the inner one)
+func computeGasLimitOfRelayingOverhead(metadata objectsMap, networkConfig *resources.NetworkConfig) uint64 {
+	innerData, _ := metadata["data"].([]byte)
+	innerNonce := getUint64Value(metadata["nonce"])
+
+	// relayedTxV2@<receiver>@<nonce>@<data>@<signature>
+	lengthOfDataField := len(builtInFunctionRelayedTxV2) + 4 +
+		2*pubkeyLength +
+		2*len(big.NewInt(0).SetUint64(innerNonce).Bytes()) +
+		2*len(innerData) +
+		2*signatureLength
+
+	return networkConfig.MinGasLimit + networkConfig.GasPerDataByte*uint64(lengthOfDataField)
+}
+
+func (service *constructionService) constructionPayloadsOfRelayedTx(
+	request *types.ConstructionPayloadsRequest,
+	tx *data.Transaction,
+) (*types.ConstructionPayloadsResponse, *types.Error) {
+	relayer, _, errTyped := service.getRelayerOfOptions(request.Metadata)
+	if errTyped != nil {
+		return nil, errTyped
+	}
+
+	relayerNonce := getUint64Value(request.Metadata["relayerNonce"])
+
+	innerSignature, err := getInnerSignatureOfMetadata(request.Metadata)
+	if err != nil {
+		return nil, service.errFactory.newErrWithOriginal(ErrMalformedValue, err)
+	}
+
+	// All the gas is provided by the relayer (the inner transaction is signed with a gas limit of 0).
+	innerTx := *tx
+	innerTx.GasLimit = 0
+
+	relayedTx, err := service.buildRelayedTxV2(&innerTx, innerSignature, relayer, relayerNonce, tx.GasLimit)
+	if err != nil {
+		return nil, service.errFactory.newErrWithOriginal(ErrMalformedValue, err)
+	}
+
+	relayedTxJson, err := json.Marshal(relayedTx)
+	if err != nil {
+		return nil, service.errFactory.newErrWithOriginal(ErrMalformedValue, err)
+	}
+
+	innerPayload, err := service.createSigningPayload(&innerTx)
+	if err != nil {
+		return nil, service.errFactory.newErrWithOriginal(ErrMalformedValue, err)
+	}
+
+	payloads := []*types.SigningPayload{innerPayload}
+
+	// The payload of the relayer is only known once the inner transaction is signed
+	if len(innerSignature) > 0 {
+		relayerPayload, err := service.createSigningPayload(relayedTx)
+		if err != nil {
+			return nil, service.errFactory.newErrWithOriginal(ErrMalformedValue, err)
+		}
+
+		payloads = append(payloads, relayerPayload)
+	}
+
+	return &types.ConstructionPayloadsResponse{
+		UnsignedTransaction: string(relayedTxJson),
+		Payloads:            payloads,
+	}, nil
+}
+
+func (service *constructionService) createSigningPayload(tx *data.Transaction) (*types.SigningPayload, error) {
+	signingPayload, err := service.computeSigningPayload(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.SigningPayload{
+		AccountIdentifier: addressToAccountIdentifier(tx.Sender),
+		SignatureType:     types.Ed25519,
+		Bytes:             signingPayload,
+	}, nil
+}
+
+func getInnerSignatureOfMetadata(metadata objectsMap) ([]byte, error) {
+	innerSignatureI, ok := metadata["innerSignature"]
+	if !ok {
+		return nil, nil
+	}
+
+	innerSignatureHex, ok := innerSignatureI.(string)
+	if !ok {
+		return nil, errors.New("inner signature is invalid")
+	}
+
+	return hex.DecodeString(innerSignatureHex)
+}
+
+// buildRelayedTxV2 wraps an inner transaction into a relayed one: "relayedTxV2@<receiver>@<nonce>@<data>@<signature>".
+// The signature of the inner transaction might be missing (if not yet known).
+func (service *constructionService) buildRelayedTxV2(
+	innerTx *data.Transaction,
+	innerSignature []byte,
+	relayer string,
+	relayerNonce uint64,
+	gasLimit uint64,
+) (*data.Transaction, error) {
+	receiverPubkey, err := service.provider.ConvertAddressToPubKey(innerTx.Receiver)
+	if err != nil {
+		return nil, err
+	}
+
+	dataField := fmt.Sprintf("%s@%s@%s@%s@%s",
+		builtInFunctionRelayedTxV2,
+		hex.EncodeToString(receiverPubkey),
+		hex.EncodeToString(big.NewInt(0).SetUint64(innerTx.Nonce).Bytes()),
+		hex.EncodeToString(innerTx.Data),
+		hex.EncodeToString(innerSignature),
+	)
+
+	return &data.Transaction{
+		Nonce:    relayerNonce,
+		Value:    "0",
+		Receiver: innerTx.Sender,
+		Sender:   relayer,
+		GasPrice: innerTx.GasPrice,
+		GasLimit: gasLimit,
+		Data:     []byte(dataField),
+		ChainID:  innerTx.ChainID,
+		Version:  innerTx.Version,
+		Options:  innerTx.Options,
+	}, nil
+}
+
+// parseRelayedTxV2 recovers the inner transaction (and its signature, if any) of a relayed transaction (v2)
+func (service *constructionService) parseRelayedTxV2(tx *data.Transaction) (*data.Transaction, []byte, bool) {
+	function, args := splitDataField(tx.Data)
+	if function != builtInFunctionRelayedTxV2 || len(args) != 4 {
+		return nil, nil, false
+	}
+
+	innerTx := &data.Transaction{
+		Nonce:    big.NewInt(0).SetBytes(args[1]).Uint64(),
+		Value:    "0",
+		Receiver: service.provider.ConvertPubKeyToAddress(args[0]),
+		Sender:   tx.Receiver,
+		GasPrice: tx.GasPrice,
+		GasLimit: 0,
+		Data:     args[2],
+		ChainID:  tx.ChainID,
+		Version:  tx.Version,
+		Options:  tx.Options,
+	}
+
+	return innerTx, args[3], true
+}
+
+func (service *constructionService) combineRelayedTx(
+	tx *data.Transaction,
+	innerTx *data.Transaction,
+	innerSignature []byte,
+	signatures []*types.Signature,
+) (*types.ConstructionCombineResponse, *types.Error) {
+	var relayerSignature *types.Signature
+	hasInnerSignatureOfRequest := false
+
+	if len(innerSignature) > 0 {
+		// The inner signature has been embedded at a previous round
+		errTyped := service.verifyEmbeddedSignature(innerTx, innerSignature)
+		if errTyped != nil {
+			return nil, errTyped
+		}
+	}
+
+	for _, signature := range signatures {
+		signer := getSignerOfSignature(signature)
+
+		switch {
+		case signer == innerTx.Sender && !hasInnerSignatureOfRequest:
+			errTyped := service.verifySignature(innerTx, signature)
+			if errTyped != nil {
+				return nil, errTyped
+			}
+
+			// At the second round, the relayed transaction already embeds the inner signature (signed over by the relayer), thus the two must match.
+			if len(innerSignature) > 0 && !bytes.Equal(innerSignature, signature.Bytes) {
+				return nil, service.errFactory.newErrWithOriginal(ErrInvalidSignature, errors.New("signature of inner transaction does not match the embedded one"))
+			}
+
+			innerSignature = signature.Bytes
+			hasInnerSignatureOfRequest = true
+		case signer == tx.Sender && relayerSignature == nil:
+			relayerSignature = signature
+		default:
+			return nil, service.errFactory.newErrWithOriginal(ErrInvalidInputParam, fmt.Errorf("unexpected signature (signer = %s)", signer))
+		}
+	}
+
+	relayedTx, err := service.buildRelayedTxV2(innerTx, innerSignature, tx.Sender, tx.Nonce, tx.GasLimit)
+	if err != nil {
+		return nil, service.errFactory.newErrWithOriginal(ErrMalformedValue, err)
+	}
+
+	if relayerSignature != nil {
+		// The relayer must have signed the relayed transaction that holds the signature of the inner transaction.
+		if len(innerSignature) == 0 {
+			return nil, service.errFactory.newErrWithOriginal(ErrInvalidInputParam, errors.New("relayer signed before the inner transaction is signed"))
+		}
+
+		errTyped := service.verifySignature(relayedTx, relayerSignature)
+		if errTyped != nil {
+			return nil, errTyped
+		}
+
+		relayedTx.Signature = hex.EncodeToString(relayerSignature.Bytes)
+	}
+
+	signedTxBytes, err := json.Marshal(relayedTx)
+	if err != nil {
+		return nil, service.errFactory.newErrWithOriginal(ErrMalformedValue, err)
+	}
+
+	return &types.ConstructionCombineResponse{
+		SignedTransaction: string(signedTxBytes),
+	}, nil
+}
+
+func getSignerOfSignature(signature *types.Signature) string {
+	if signature.SigningPayload == nil || signature.SigningPayload.AccountIdentifier == nil {
+		return ""
+	}
+
+	return signature.SigningPayload.AccountIdentifier.Address
+}
+
+// getSignersOfRelayedTx returns the signers of a relayed transaction: the user (if the inner transaction is signed), then the relayer (if signed)
+func getSignersOfRelayedTx(tx *data.Transaction, innerTx *data.Transaction, innerSignature []byte) []*types.AccountIdentifier {
+	signers := make([]*types.AccountIdentifier, 0, 2)
+
+	if len(innerSignature) > 0 {
+		signers = append(signers, addressToAccountIdentifier(innerTx.Sender))
+	}
+	if len(tx.Signature) > 0 {
+		signers = append(signers, addressToAccountIdentifier(tx.Sender))
+	}
+
+	return signers
+}
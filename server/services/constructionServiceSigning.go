@@ -0,0 +1,76 @@
+package services
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	"github.com/ElrondNetwork/elrond-proxy-go/data"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// isSigningByHashRequested tells whether the transaction to be constructed should be signed by hash (instead of its serialized form)
+func isSigningByHashRequested(options objectsMap) bool {
+	signByHash, ok := options["signByHash"].(bool)
+	return ok && signByHash
+}
+
+// computeSigningPayload computes the bytes to be signed, exactly as the Node computes them when verifying the signature
+// (canonical serialization of the transaction or, for transactions signed by hash, its hash).
+// The serialization of the unsigned transaction (as returned to the client) must not be used instead.
+func (service *constructionService) computeSigningPayload(tx *data.Transaction) ([]byte, error) {
+	return service.provider.ComputeTransactionSigningPayload(tx)
+}
+
+// verifySignature verifies a signature provided to /construction/combine: it must have been produced by the sender of the transaction,
+// over exactly the bytes the Node will verify it against. This way, bad signatures (or mixed-up keys) are caught before submitting the transaction.
+func (service *constructionService) verifySignature(tx *data.Transaction, signature *types.Signature) *types.Error {
+	signingPayload, err := service.computeSigningPayload(tx)
+	if err != nil {
+		return service.errFactory.newErrWithOriginal(ErrMalformedValue, err)
+	}
+
+	if signature.SigningPayload == nil || !bytes.Equal(signingPayload, signature.SigningPayload.Bytes) {
+		return service.errFactory.newErrWithOriginal(ErrInvalidSignature, errors.New("signature has been produced over an unexpected payload"))
+	}
+	if signature.SignatureType != types.Ed25519 {
+		return service.errFactory.newErrWithOriginal(ErrInvalidSignature, fmt.Errorf("unsupported signature type: %s", signature.SignatureType))
+	}
+	if signature.PublicKey == nil || len(signature.PublicKey.Bytes) != ed25519.PublicKeySize {
+		return service.errFactory.newErrWithOriginal(ErrInvalidSignature, errors.New("missing or malformed public key"))
+	}
+	if signature.PublicKey.CurveType != types.Edwards25519 {
+		return service.errFactory.newErr(ErrUnsupportedCurveType)
+	}
+
+	signer := service.provider.ConvertPubKeyToAddress(signature.PublicKey.Bytes)
+	if signer != tx.Sender {
+		return service.errFactory.newErrWithOriginal(ErrInvalidSignature, fmt.Errorf("public key does not belong to the sender: signer = %s, sender = %s", signer, tx.Sender))
+	}
+
+	return service.verifySignatureBytes(signature.PublicKey.Bytes, signingPayload, signature.Bytes)
+}
+
+// verifyEmbeddedSignature verifies a signature that is already part of the transaction to be combined (e.g. the one of an inner transaction)
+func (service *constructionService) verifyEmbeddedSignature(tx *data.Transaction, signature []byte) *types.Error {
+	signingPayload, err := service.computeSigningPayload(tx)
+	if err != nil {
+		return service.errFactory.newErrWithOriginal(ErrMalformedValue, err)
+	}
+
+	senderPubkey, err := service.provider.ConvertAddressToPubKey(tx.Sender)
+	if err != nil {
+		return service.errFactory.newErrWithOriginal(ErrMalformedValue, err)
+	}
+
+	return service.verifySignatureBytes(senderPubkey, signingPayload, signature)
+}
+
+func (service *constructionService) verifySignatureBytes(pubkey []byte, signingPayload []byte, signature []byte) *types.Error {
+	if !ed25519.Verify(pubkey, signingPayload, signature) {
+		return service.errFactory.newErrWithOriginal(ErrInvalidSignature, errors.New("signature verification failed"))
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ElrondNetwork/rosetta/server/resources"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// getCustomCurrencyOfOptions returns the custom currency (ESDT) to be transferred, if any
+func (service *constructionService) getCustomCurrencyOfOptions(options objectsMap) (resources.CustomCurrency, bool, *types.Error) {
+	currencySymbolI, ok := options["currencySymbol"]
+	if !ok {
+		return resources.CustomCurrency{}, false, nil
+	}
+
+	currencySymbol, ok := currencySymbolI.(string)
+	if !ok {
+		return resources.CustomCurrency{}, false, service.errFactory.newErrWithOriginal(ErrMalformedValue, errors.New("currency symbol is invalid"))
+	}
+
+	currency, ok := service.provider.GetCustomCurrencyBySymbol(currencySymbol)
+	if !ok || !service.isCustomCurrencyActiveForConstruction(currency) {
+		return resources.CustomCurrency{}, false, service.errFactory.newErr(ErrUnsupportedCurrency)
+	}
+
+	return currency, true, nil
+}
+
+// computeDataFieldOfOptions returns the data field of the transaction to be constructed,
+// either built automatically (for transfers of custom currencies) or as provided by the caller.
+func (service *constructionService) computeDataFieldOfOptions(options objectsMap) ([]byte, *types.Error) {
+	currency, isCustomCurrencyTransfer, errTyped := service.getCustomCurrencyOfOptions(options)
+	if errTyped != nil {
+		return nil, errTyped
+	}
+
+	if isCustomCurrencyTransfer {
+		if _, hasData := options["data"]; hasData {
+			return nil, service.errFactory.newErrWithOriginal(ErrConstructionCheck, errors.New("data field cannot be provided for transfers of custom currencies"))
+		}
+
+		value := fmt.Sprintf("%v", options["value"])
+		dataField, err := buildESDTTransferData(currency.Identifier, value)
+		if err != nil {
+			return nil, service.errFactory.newErrWithOriginal(ErrMalformedValue, err)
+		}
+
+		return dataField, nil
+	}
+
+	if dataFieldI, ok := options["data"]; ok {
+		return []byte(fmt.Sprintf("%v", dataFieldI)), nil
+	}
+
+	return nil, nil
+}
+
+func (service *constructionService) isCurrencySupportedForConstruction(currency *types.Currency) bool {
+	if service.extension.isNativeCurrencySymbol(currency.Symbol) {
+		return true
+	}
+
+	customCurrency, ok := service.provider.GetCustomCurrencyBySymbol(currency.Symbol)
+	if !ok || !service.isCustomCurrencyActiveForConstruction(customCurrency) {
+		return false
+	}
+
+	// Only fungible tokens (with no nonce suffix) can be transferred by means of "ESDTTransfer".
+	return isFungibleTokenIdentifier(customCurrency.Identifier)
+}
+
+// isCustomCurrencyActiveForConstruction tells whether a custom currency is active at the latest block.
+// In offline mode, the latest block isn't known - then, the activation is checked by /construction/metadata (online).
+func (service *constructionService) isCustomCurrencyActiveForConstruction(currency resources.CustomCurrency) bool {
+	if currency.ActivationNonce == 0 || service.provider.IsOffline() {
+		return true
+	}
+
+	latestBlock, err := service.provider.GetLatestBlockSummary()
+	if err != nil {
+		log.Warn("isCustomCurrencyActiveForConstruction(): cannot get latest block", "err", err)
+		return false
+	}
+
+	return isCustomCurrencyActive(currency, latestBlock.Nonce)
+}
+
+func isFungibleTokenIdentifier(identifier string) bool {
+	return strings.Count(identifier, "-") == 1
+}
+
+// buildESDTTransferData builds the data field "ESDTTransfer@<token>@<amount>" (arguments are hex-encoded)
+func buildESDTTransferData(tokenIdentifier string, value string) ([]byte, error) {
+	valueBig, ok := big.NewInt(0).SetString(value, 10)
+	if !ok || valueBig.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid value of token transfer: %s", value)
+	}
+
+	dataField := fmt.Sprintf("%s@%s@%s",
+		builtInFunctionESDTTransfer,
+		hex.EncodeToString([]byte(tokenIdentifier)),
+		hex.EncodeToString(valueBig.Bytes()),
+	)
+
+	return []byte(dataField), nil
+}
+
+// parseESDTTransferData parses a data field of shape "ESDTTransfer@<token>@<amount>"
+func parseESDTTransferData(dataField []byte) (string, string, bool) {
+	parts := bytes.Split(dataField, []byte("@"))
+	if len(parts) != 3 || string(parts[0]) != builtInFunctionESDTTransfer {
+		return "", "", false
+	}
+
+	tokenIdentifier, err := hex.DecodeString(string(parts[1]))
+	if err != nil {
+		return "", "", false
+	}
+
+	valueBytes, err := hex.DecodeString(string(parts[2]))
+	if err != nil {
+		return "", "", false
+	}
+
+	value := big.NewInt(0).SetBytes(valueBytes)
+	return string(tokenIdentifier), value.String(), true
+}
@@ -1,10 +1,17 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 
+	"github.com/ElrondNetwork/elrond-go-core/hashing/keccak"
 	"github.com/ElrondNetwork/elrond-proxy-go/data"
+	"github.com/ElrondNetwork/rosetta/server/resources"
 	"github.com/ElrondNetwork/rosetta/testscommon"
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/stretchr/testify/require"
@@ -78,7 +85,7 @@ func TestConstructionService_ConstructionMetadata(t *testing.T) {
 		"feeMultiplier": 1.1,
 		"data":          "hello",
 		"value":         "1234",
-		"maxFee":        "1",
+		"maxFee":        "63250000000000",
 		"type":          opTransfer,
 	}
 	response, err := service.ConstructionMetadata(context.Background(),
@@ -90,6 +97,26 @@ func TestConstructionService_ConstructionMetadata(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, "63250000000000", response.SuggestedFee[0].Value)
 
+	// When the max fee would be exceeded, the gas price is capped, then (below the minimum gas price) an error is returned
+	options["maxFee"] = "60000000000000"
+	responseWithCappedFee, err := service.ConstructionMetadata(context.Background(),
+		&types.ConstructionMetadataRequest{
+			Options: options,
+		},
+	)
+	require.Nil(t, err)
+	require.Equal(t, "59999999950000", responseWithCappedFee.SuggestedFee[0].Value)
+	require.Equal(t, uint64(1043478260), responseWithCappedFee.Metadata["gasPrice"])
+
+	options["maxFee"] = "1"
+	_, err = service.ConstructionMetadata(context.Background(),
+		&types.ConstructionMetadataRequest{
+			Options: options,
+		},
+	)
+	require.Equal(t, ErrMaxFeeExceeded, errCode(err.Code))
+	options["maxFee"] = "63250000000000"
+
 	expectedMetadata := map[string]interface{}{
 		"receiver": testscommon.TestAddressBob,
 		"sender":   testscommon.TestAddressAlice,
@@ -105,6 +132,93 @@ func TestConstructionService_ConstructionMetadata(t *testing.T) {
 	require.Equal(t, expectedMetadata, response.Metadata)
 }
 
+func TestConstructionService_ConstructionMetadataOfContractCall(t *testing.T) {
+	networkProvider := testscommon.NewNetworkProviderMock()
+	networkProvider.MockNetworkConfig.ChainID = "T"
+	networkProvider.MockGasLimitSafetyMargin = 10
+	networkProvider.MockAccountsByAddress[testscommon.TestAddressAlice] = &data.Account{
+		Address: testscommon.TestAddressAlice,
+		Nonce:   42,
+	}
+
+	var simulatedTx *data.Transaction
+	networkProvider.SimulateTransactionCostCalled = func(tx *data.Transaction) (*data.TxCostResponseData, error) {
+		simulatedTx = tx
+		return &data.TxCostResponseData{TxCost: 5000000}, nil
+	}
+
+	service := NewConstructionService(networkProvider)
+
+	options := objectsMap{
+		"receiver": testscommon.TestAddressOfContract,
+		"sender":   testscommon.TestAddressAlice,
+		"data":     "delegate",
+		"value":    "1000000000000000000",
+		"type":     opTransfer,
+	}
+
+	metadata := func() (*types.ConstructionMetadataResponse, *types.Error) {
+		return service.ConstructionMetadata(context.Background(),
+			&types.ConstructionMetadataRequest{
+				Options: options,
+			},
+		)
+	}
+
+	response, err := metadata()
+	require.Nil(t, err)
+	require.Equal(t, uint64(5500000), response.Metadata["gasLimit"])
+	require.Equal(t, "5500000000000000", response.SuggestedFee[0].Value)
+	require.Equal(t, &data.Transaction{
+		Nonce:    42,
+		Value:    "1000000000000000000",
+		Receiver: testscommon.TestAddressOfContract,
+		Sender:   testscommon.TestAddressAlice,
+		GasPrice: 1000000000,
+		Data:     []byte("delegate"),
+		ChainID:  "T",
+		Version:  1,
+	}, simulatedTx)
+	_, isGasLimitSetOnRequest := options["gasLimit"]
+	require.False(t, isGasLimitSetOnRequest)
+
+	// Simulation fails
+	networkProvider.SimulateTransactionCostCalled = func(tx *data.Transaction) (*data.TxCostResponseData, error) {
+		return &data.TxCostResponseData{RetMessage: "user error: function not found"}, nil
+	}
+	_, err = metadata()
+	require.Equal(t, ErrTransactionSimulationFailed, errCode(err.Code))
+	require.Contains(t, err.Details["originalError"], "function not found")
+
+	// Simulation cannot be performed
+	networkProvider.SimulateTransactionCostCalled = func(tx *data.Transaction) (*data.TxCostResponseData, error) {
+		return nil, errors.New("observer is down")
+	}
+	_, err = metadata()
+	require.Equal(t, ErrUnableToSimulateTransaction, errCode(err.Code))
+	require.True(t, err.Retriable)
+
+	// No simulation when the gas limit is provided, or for regular transfers
+	simulatedTx = nil
+	networkProvider.SimulateTransactionCostCalled = func(tx *data.Transaction) (*data.TxCostResponseData, error) {
+		simulatedTx = tx
+		return &data.TxCostResponseData{TxCost: 5000000}, nil
+	}
+
+	options["gasLimit"] = uint64(6000000)
+	response, err = metadata()
+	require.Nil(t, err)
+	require.Equal(t, uint64(6000000), response.Metadata["gasLimit"])
+	require.Nil(t, simulatedTx)
+
+	delete(options, "gasLimit")
+	options["receiver"] = testscommon.TestAddressBob
+	response, err = metadata()
+	require.Nil(t, err)
+	require.Equal(t, uint64(62000), response.Metadata["gasLimit"])
+	require.Nil(t, simulatedTx)
+}
+
 func TestConstructionService_ConstructionPayloads(t *testing.T) {
 	networkProvider := testscommon.NewNetworkProviderMock()
 	networkProvider.MockNetworkConfig.ChainID = "T"
@@ -199,25 +313,68 @@ func TestConstructionService_ConstructionParse(t *testing.T) {
 func TestConstructionService_ConstructionCombine(t *testing.T) {
 	networkProvider := testscommon.NewNetworkProviderMock()
 	networkProvider.MockNetworkConfig.ChainID = "T"
+	// Signatures are verified locally
+	networkProvider.MockIsOffline = true
 
 	service := NewConstructionService(networkProvider)
 
 	unsignedTx := "{\"nonce\":42,\"value\":\"1234\",\"receiver\":\"erd1spyavw0956vq68xj8y4tenjpq2wd5a9p2c6j8gsz7ztyrnpxrruqzu66jx\",\"sender\":\"erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th\",\"gasPrice\":1100000000,\"gasLimit\":57500,\"data\":\"aGVsbG8=\",\"chainID\":\"T\",\"version\":1}"
+	signingPayload := &types.SigningPayload{
+		AccountIdentifier: addressToAccountIdentifier(testscommon.TestAddressAlice),
+		Bytes:             []byte(unsignedTx),
+		SignatureType:     types.Ed25519,
+	}
 
-	response, err := service.ConstructionCombine(context.Background(),
-		&types.ConstructionCombineRequest{
-			UnsignedTransaction: unsignedTx,
-			Signatures: []*types.Signature{
-				{
-					Bytes: []byte{0xaa, 0xbb},
-				},
+	combine := func(signature *types.Signature) (*types.ConstructionCombineResponse, *types.Error) {
+		return service.ConstructionCombine(context.Background(),
+			&types.ConstructionCombineRequest{
+				UnsignedTransaction: unsignedTx,
+				Signatures:          []*types.Signature{signature},
 			},
-		},
-	)
+		)
+	}
 
-	signedTx := "{\"nonce\":42,\"value\":\"1234\",\"receiver\":\"erd1spyavw0956vq68xj8y4tenjpq2wd5a9p2c6j8gsz7ztyrnpxrruqzu66jx\",\"sender\":\"erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th\",\"gasPrice\":1100000000,\"gasLimit\":57500,\"data\":\"aGVsbG8=\",\"signature\":\"aabb\",\"chainID\":\"T\",\"version\":1}"
-	require.Nil(t, err)
-	require.Equal(t, signedTx, response.SignedTransaction)
+	t.Run("with good signature", func(t *testing.T) {
+		signature := newTestSignature(signingPayload, testscommon.TestSecretKeyAlice)
+		response, err := combine(signature)
+		require.Nil(t, err)
+
+		signedTx := "{\"nonce\":42,\"value\":\"1234\",\"receiver\":\"erd1spyavw0956vq68xj8y4tenjpq2wd5a9p2c6j8gsz7ztyrnpxrruqzu66jx\",\"sender\":\"erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th\",\"gasPrice\":1100000000,\"gasLimit\":57500,\"data\":\"aGVsbG8=\",\"signature\":\"" + hex.EncodeToString(signature.Bytes) + "\",\"chainID\":\"T\",\"version\":1}"
+		require.Equal(t, signedTx, response.SignedTransaction)
+	})
+
+	t.Run("with signature over another payload", func(t *testing.T) {
+		otherPayload := &types.SigningPayload{Bytes: []byte(strings.Replace(unsignedTx, "\"nonce\":42", "\"nonce\":43", 1))}
+		_, err := combine(newTestSignature(otherPayload, testscommon.TestSecretKeyAlice))
+		require.Equal(t, ErrInvalidSignature, errCode(err.Code))
+	})
+
+	t.Run("with bad signature", func(t *testing.T) {
+		signature := newTestSignature(signingPayload, testscommon.TestSecretKeyAlice)
+		signature.Bytes[0] ^= 0xff
+		_, err := combine(signature)
+		require.Equal(t, ErrInvalidSignature, errCode(err.Code))
+	})
+
+	t.Run("with key of another account", func(t *testing.T) {
+		_, err := combine(newTestSignature(signingPayload, testscommon.TestSecretKeyBob))
+		require.Equal(t, ErrInvalidSignature, errCode(err.Code))
+		require.Contains(t, err.Details["originalError"], "public key does not belong to the sender")
+	})
+
+	t.Run("with unsupported curve", func(t *testing.T) {
+		signature := newTestSignature(signingPayload, testscommon.TestSecretKeyAlice)
+		signature.PublicKey.CurveType = types.Secp256k1
+		_, err := combine(signature)
+		require.Equal(t, ErrUnsupportedCurveType, errCode(err.Code))
+	})
+
+	t.Run("without public key", func(t *testing.T) {
+		signature := newTestSignature(signingPayload, testscommon.TestSecretKeyAlice)
+		signature.PublicKey = nil
+		_, err := combine(signature)
+		require.Equal(t, ErrInvalidSignature, errCode(err.Code))
+	})
 }
 
 func TestConstructionService_ConstructionDerive(t *testing.T) {
@@ -303,6 +460,508 @@ func TestConstructionService_CreateOperationsFromPreparedTx(t *testing.T) {
 		},
 	}
 
-	operations := service.createOperationsFromPreparedTx(preparedTx)
+	operations, err := service.createOperationsFromPreparedTx(preparedTx)
+	require.Nil(t, err)
 	require.Equal(t, expectedOperations, operations)
 }
+
+func TestConstructionService_CustomCurrencyTransfer(t *testing.T) {
+	networkProvider := testscommon.NewNetworkProviderMock()
+	networkProvider.MockNetworkConfig.ChainID = "T"
+	networkProvider.MockCustomCurrencies = []resources.CustomCurrency{{Identifier: "ROSETTA-3a2edf", Symbol: "ROSETTA", Decimals: 2}}
+	networkProvider.MockAccountsByAddress[testscommon.TestAddressAlice] = &data.Account{
+		Address: testscommon.TestAddressAlice,
+		Nonce:   42,
+	}
+
+	extension := newNetworkProviderExtension(networkProvider)
+	service := NewConstructionService(networkProvider)
+	currency := networkProvider.MockCustomCurrencies[0]
+
+	operations := []*types.Operation{
+		{
+			OperationIdentifier: indexToOperationIdentifier(0),
+			Type:                opTransfer,
+			Account:             addressToAccountIdentifier(testscommon.TestAddressAlice),
+			Amount:              extension.valueToCustomAmount("-1234", currency),
+		},
+		{
+			OperationIdentifier: indexToOperationIdentifier(1),
+			Type:                opTransfer,
+			Account:             addressToAccountIdentifier(testscommon.TestAddressBob),
+			Amount:              extension.valueToCustomAmount("1234", currency),
+		},
+	}
+
+	preprocessResponse, err := service.ConstructionPreprocess(context.Background(),
+		&types.ConstructionPreprocessRequest{
+			Operations: operations,
+		},
+	)
+	require.Nil(t, err)
+	require.Equal(t, map[string]interface{}{
+		"receiver":       testscommon.TestAddressBob,
+		"sender":         testscommon.TestAddressAlice,
+		"value":          "1234",
+		"type":           opTransfer,
+		"currencySymbol": "ROSETTA",
+	}, preprocessResponse.Options)
+
+	metadataResponse, err := service.ConstructionMetadata(context.Background(),
+		&types.ConstructionMetadataRequest{
+			Options: preprocessResponse.Options,
+		},
+	)
+	require.Nil(t, err)
+	require.Equal(t, "319000000000000", metadataResponse.SuggestedFee[0].Value)
+	require.Equal(t, map[string]interface{}{
+		"receiver": testscommon.TestAddressBob,
+		"sender":   testscommon.TestAddressAlice,
+		"chainID":  "T",
+		"version":  1,
+		"data":     []byte("ESDTTransfer@524f53455454412d336132656466@04d2"),
+		"value":    "0",
+		"nonce":    uint64(42),
+		"gasPrice": uint64(1000000000),
+		"gasLimit": uint64(319000),
+	}, metadataResponse.Metadata)
+
+	payloadsResponse, err := service.ConstructionPayloads(context.Background(),
+		&types.ConstructionPayloadsRequest{
+			Operations: operations,
+			Metadata:   metadataResponse.Metadata,
+		},
+	)
+	require.Nil(t, err)
+
+	parseResponse, err := service.ConstructionParse(context.Background(),
+		&types.ConstructionParseRequest{
+			Signed:      false,
+			Transaction: payloadsResponse.UnsignedTransaction,
+		},
+	)
+	require.Nil(t, err)
+	require.Equal(t, operations, parseResponse.Operations)
+
+	// Data field cannot be provided for transfers of custom currencies
+	preprocessResponse.Options["data"] = "hello"
+	_, err = service.ConstructionMetadata(context.Background(),
+		&types.ConstructionMetadataRequest{
+			Options: preprocessResponse.Options,
+		},
+	)
+	require.Equal(t, ErrConstructionCheck, errCode(err.Code))
+
+	// Unknown currency
+	operations[0].Amount.Currency = &types.Currency{Symbol: "UNKNOWN-abcdef", Decimals: 2}
+	operations[1].Amount.Currency = &types.Currency{Symbol: "UNKNOWN-abcdef", Decimals: 2}
+	_, err = service.ConstructionPreprocess(context.Background(),
+		&types.ConstructionPreprocessRequest{
+			Operations: operations,
+		},
+	)
+	require.Equal(t, ErrUnsupportedCurrency, errCode(err.Code))
+
+	// Transfer of an unknown currency isn't parsed as a native transfer
+	unsignedTx, _ := json.Marshal(&data.Transaction{
+		Sender:   testscommon.TestAddressAlice,
+		Receiver: testscommon.TestAddressBob,
+		Value:    "0",
+		Data:     []byte("ESDTTransfer@554e4b4e4f574e2d616263646566@04d2"),
+	})
+	_, err = service.ConstructionParse(context.Background(),
+		&types.ConstructionParseRequest{
+			Signed:      false,
+			Transaction: string(unsignedTx),
+		},
+	)
+	require.Equal(t, ErrUnsupportedCurrency, errCode(err.Code))
+}
+
+func TestConstructionService_CustomCurrencyNotYetActive(t *testing.T) {
+	networkProvider := testscommon.NewNetworkProviderMock()
+	networkProvider.MockCustomCurrencies = []resources.CustomCurrency{{Identifier: "FUTURE-abcdef", Symbol: "FUTURE", Decimals: 2, ActivationNonce: 43}}
+	networkProvider.MockLatestBlockSummary.Nonce = 42
+
+	extension := newNetworkProviderExtension(networkProvider)
+	service := NewConstructionService(networkProvider)
+	currency := networkProvider.MockCustomCurrencies[0]
+
+	operations := []*types.Operation{
+		{
+			OperationIdentifier: indexToOperationIdentifier(0),
+			Type:                opTransfer,
+			Account:             addressToAccountIdentifier(testscommon.TestAddressAlice),
+			Amount:              extension.valueToCustomAmount("-1234", currency),
+		},
+		{
+			OperationIdentifier: indexToOperationIdentifier(1),
+			Type:                opTransfer,
+			Account:             addressToAccountIdentifier(testscommon.TestAddressBob),
+			Amount:              extension.valueToCustomAmount("1234", currency),
+		},
+	}
+
+	unsignedTx, _ := json.Marshal(&data.Transaction{
+		Sender:   testscommon.TestAddressAlice,
+		Receiver: testscommon.TestAddressBob,
+		Value:    "0",
+		Data:     []byte("ESDTTransfer@4655545552452d616263646566@04d2"),
+	})
+
+	preprocess := func() *types.Error {
+		_, err := service.ConstructionPreprocess(context.Background(), &types.ConstructionPreprocessRequest{Operations: operations})
+		return err
+	}
+
+	parse := func() *types.Error {
+		_, err := service.ConstructionParse(context.Background(), &types.ConstructionParseRequest{Transaction: string(unsignedTx)})
+		return err
+	}
+
+	t.Run("before activation", func(t *testing.T) {
+		require.Equal(t, ErrUnsupportedCurrency, errCode(preprocess().Code))
+		require.Equal(t, ErrUnsupportedCurrency, errCode(parse().Code))
+	})
+
+	t.Run("after activation", func(t *testing.T) {
+		networkProvider.MockLatestBlockSummary.Nonce = 43
+		require.Nil(t, preprocess())
+		require.Nil(t, parse())
+	})
+}
+
+func TestConstructionService_RelayedTransfer(t *testing.T) {
+	networkProvider := testscommon.NewNetworkProviderMock()
+	networkProvider.MockNetworkConfig.ChainID = "T"
+	networkProvider.MockCustomCurrencies = []resources.CustomCurrency{{Identifier: "ROSETTA-3a2edf", Symbol: "ROSETTA", Decimals: 2}}
+
+	relayer := testscommon.TestAddressCarol
+	networkProvider.MockAccountsByAddress[testscommon.TestAddressAlice] = &data.Account{Address: testscommon.TestAddressAlice, Nonce: 42}
+	networkProvider.MockAccountsByAddress[relayer] = &data.Account{Address: relayer, Nonce: 7}
+
+	extension := newNetworkProviderExtension(networkProvider)
+	service := NewConstructionService(networkProvider)
+	currency := networkProvider.MockCustomCurrencies[0]
+
+	operations := []*types.Operation{
+		{
+			OperationIdentifier: indexToOperationIdentifier(0),
+			Type:                opTransfer,
+			Account:             addressToAccountIdentifier(testscommon.TestAddressAlice),
+			Amount:              extension.valueToCustomAmount("-1234", currency),
+		},
+		{
+			OperationIdentifier: indexToOperationIdentifier(1),
+			Type:                opTransfer,
+			Account:             addressToAccountIdentifier(testscommon.TestAddressBob),
+			Amount:              extension.valueToCustomAmount("1234", currency),
+		},
+	}
+
+	preprocessResponse, err := service.ConstructionPreprocess(context.Background(),
+		&types.ConstructionPreprocessRequest{
+			Operations: operations,
+			Metadata:   objectsMap{"relayer": relayer},
+		},
+	)
+	require.Nil(t, err)
+	require.Equal(t, relayer, preprocessResponse.Options["relayer"])
+
+	metadataResponse, err := service.ConstructionMetadata(context.Background(),
+		&types.ConstructionMetadataRequest{
+			Options: preprocessResponse.Options,
+		},
+	)
+	require.Nil(t, err)
+	// 319000 (inner transaction) + 50000 + 1500 * 301 (relayedTxV2@<receiver>@2a@<data>@<signature>)
+	require.Equal(t, uint64(820500), metadataResponse.Metadata["gasLimit"])
+	require.Equal(t, "820500000000000", metadataResponse.SuggestedFee[0].Value)
+	require.Equal(t, relayer, metadataResponse.Metadata["relayer"])
+	require.Equal(t, uint64(7), metadataResponse.Metadata["relayerNonce"])
+
+	// First round: the user signs the inner transaction
+	payloadsResponse, err := service.ConstructionPayloads(context.Background(),
+		&types.ConstructionPayloadsRequest{
+			Operations: operations,
+			Metadata:   metadataResponse.Metadata,
+		},
+	)
+	require.Nil(t, err)
+	require.Len(t, payloadsResponse.Payloads, 1)
+	require.Equal(t, testscommon.TestAddressAlice, payloadsResponse.Payloads[0].AccountIdentifier.Address)
+
+	innerPayload := "{\"nonce\":42,\"value\":\"0\",\"receiver\":\"erd1spyavw0956vq68xj8y4tenjpq2wd5a9p2c6j8gsz7ztyrnpxrruqzu66jx\",\"sender\":\"erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th\",\"gasPrice\":1000000000,\"gasLimit\":0,\"data\":\"RVNEVFRyYW5zZmVyQDUyNGY1MzQ1NTQ1NDQxMmQzMzYxMzI2NTY0NjZAMDRkMg==\",\"chainID\":\"T\",\"version\":1}"
+	require.Equal(t, innerPayload, string(payloadsResponse.Payloads[0].Bytes))
+
+	parseResponse, err := service.ConstructionParse(context.Background(),
+		&types.ConstructionParseRequest{
+			Signed:      false,
+			Transaction: payloadsResponse.UnsignedTransaction,
+		},
+	)
+	require.Nil(t, err)
+	require.Equal(t, operations, parseResponse.Operations)
+
+	innerSignature := newTestSignature(payloadsResponse.Payloads[0], testscommon.TestSecretKeyAlice)
+	combineResponse, err := service.ConstructionCombine(context.Background(),
+		&types.ConstructionCombineRequest{
+			UnsignedTransaction: payloadsResponse.UnsignedTransaction,
+			Signatures: []*types.Signature{
+				innerSignature,
+			},
+		},
+	)
+	require.Nil(t, err)
+
+	parseResponse, err = service.ConstructionParse(context.Background(),
+		&types.ConstructionParseRequest{
+			Signed:      true,
+			Transaction: combineResponse.SignedTransaction,
+		},
+	)
+	require.Nil(t, err)
+	require.Equal(t, []*types.AccountIdentifier{addressToAccountIdentifier(testscommon.TestAddressAlice)}, parseResponse.AccountIdentifierSigners)
+
+	// Second round: both the user and the relayer sign (the relayed transaction holds the inner signature)
+	metadataResponse.Metadata["innerSignature"] = hex.EncodeToString(innerSignature.Bytes)
+	payloadsResponse, err = service.ConstructionPayloads(context.Background(),
+		&types.ConstructionPayloadsRequest{
+			Operations: operations,
+			Metadata:   metadataResponse.Metadata,
+		},
+	)
+	require.Nil(t, err)
+	require.Len(t, payloadsResponse.Payloads, 2)
+	require.Equal(t, testscommon.TestAddressAlice, payloadsResponse.Payloads[0].AccountIdentifier.Address)
+	require.Equal(t, innerPayload, string(payloadsResponse.Payloads[0].Bytes))
+	require.Equal(t, relayer, payloadsResponse.Payloads[1].AccountIdentifier.Address)
+	require.Equal(t, combineResponse.SignedTransaction, payloadsResponse.UnsignedTransaction)
+
+	innerSignature = newTestSignature(payloadsResponse.Payloads[0], testscommon.TestSecretKeyAlice)
+	relayerSignature := newTestSignature(payloadsResponse.Payloads[1], testscommon.TestSecretKeyCarol)
+	combineResponse, err = service.ConstructionCombine(context.Background(),
+		&types.ConstructionCombineRequest{
+			UnsignedTransaction: payloadsResponse.UnsignedTransaction,
+			Signatures: []*types.Signature{
+				innerSignature,
+				relayerSignature,
+			},
+		},
+	)
+	require.Nil(t, err)
+
+	// The relayer's signature alone is sufficient, as well (the inner signature is already embedded)
+	combineResponseOfRelayer, err := service.ConstructionCombine(context.Background(),
+		&types.ConstructionCombineRequest{
+			UnsignedTransaction: payloadsResponse.UnsignedTransaction,
+			Signatures: []*types.Signature{
+				relayerSignature,
+			},
+		},
+	)
+	require.Nil(t, err)
+	require.Equal(t, combineResponse.SignedTransaction, combineResponseOfRelayer.SignedTransaction)
+
+	signedTx, _ := getTxFromRequest(combineResponse.SignedTransaction)
+	require.Equal(t, relayer, signedTx.Sender)
+	require.Equal(t, testscommon.TestAddressAlice, signedTx.Receiver)
+	require.Equal(t, uint64(7), signedTx.Nonce)
+	require.Equal(t, hex.EncodeToString(relayerSignature.Bytes), signedTx.Signature)
+	require.Equal(t, "relayedTxV2@"+testscommon.TestPubKeyHexBob+"@2a@"+hex.EncodeToString([]byte("ESDTTransfer@524f53455454412d336132656466@04d2"))+"@"+hex.EncodeToString(innerSignature.Bytes), string(signedTx.Data))
+
+	parseResponse, err = service.ConstructionParse(context.Background(),
+		&types.ConstructionParseRequest{
+			Signed:      true,
+			Transaction: combineResponse.SignedTransaction,
+		},
+	)
+	require.Nil(t, err)
+	require.Equal(t, operations, parseResponse.Operations)
+	require.Equal(t, []*types.AccountIdentifier{
+		addressToAccountIdentifier(testscommon.TestAddressAlice),
+		addressToAccountIdentifier(relayer),
+	}, parseResponse.AccountIdentifierSigners)
+
+	// The relayer must sign the relayed transaction that holds the inner signature
+	otherPayload := &types.SigningPayload{AccountIdentifier: addressToAccountIdentifier(relayer), Bytes: []byte("something else")}
+	_, err = service.ConstructionCombine(context.Background(),
+		&types.ConstructionCombineRequest{
+			UnsignedTransaction: payloadsResponse.UnsignedTransaction,
+			Signatures: []*types.Signature{
+				newTestSignature(otherPayload, testscommon.TestSecretKeyCarol),
+			},
+		},
+	)
+	require.Equal(t, ErrInvalidSignature, errCode(err.Code))
+
+	// The user must sign the inner transaction
+	otherPayload = &types.SigningPayload{AccountIdentifier: addressToAccountIdentifier(testscommon.TestAddressAlice), Bytes: []byte("something else")}
+	_, err = service.ConstructionCombine(context.Background(),
+		&types.ConstructionCombineRequest{
+			UnsignedTransaction: payloadsResponse.UnsignedTransaction,
+			Signatures: []*types.Signature{
+				newTestSignature(otherPayload, testscommon.TestSecretKeyAlice),
+				relayerSignature,
+			},
+		},
+	)
+	require.Equal(t, ErrInvalidSignature, errCode(err.Code))
+
+	// The embedded inner signature is verified, as well
+	tamperedTx, _ := getTxFromRequest(payloadsResponse.UnsignedTransaction)
+	tamperedTx.Data = bytes.Replace(tamperedTx.Data, []byte("@2a@"), []byte("@2b@"), 1)
+	tamperedTxJson, _ := json.Marshal(tamperedTx)
+	_, err = service.ConstructionCombine(context.Background(),
+		&types.ConstructionCombineRequest{
+			UnsignedTransaction: string(tamperedTxJson),
+			Signatures: []*types.Signature{
+				relayerSignature,
+			},
+		},
+	)
+	require.Equal(t, ErrInvalidSignature, errCode(err.Code))
+
+	// Native value cannot be relayed (by means of relayed transactions v2)
+	preprocessResponse.Options["value"] = "1"
+	delete(preprocessResponse.Options, "currencySymbol")
+	_, err = service.ConstructionMetadata(context.Background(),
+		&types.ConstructionMetadataRequest{
+			Options: preprocessResponse.Options,
+		},
+	)
+	require.Equal(t, ErrConstructionCheck, errCode(err.Code))
+}
+
+func TestConstructionService_GuardedTransactionIsRejected(t *testing.T) {
+	networkProvider := testscommon.NewNetworkProviderMock()
+	networkProvider.MockComputedTransactionHash = "aaaa"
+	extension := newNetworkProviderExtension(networkProvider)
+	service := NewConstructionService(networkProvider)
+
+	operations := []*types.Operation{
+		{
+			OperationIdentifier: indexToOperationIdentifier(0),
+			Type:                opTransfer,
+			Account:             addressToAccountIdentifier(testscommon.TestAddressAlice),
+			Amount:              extension.valueToNativeAmount("-1234"),
+		},
+		{
+			OperationIdentifier: indexToOperationIdentifier(1),
+			Type:                opTransfer,
+			Account:             addressToAccountIdentifier(testscommon.TestAddressBob),
+			Amount:              extension.valueToNativeAmount("1234"),
+		},
+	}
+
+	preprocessResponse, err := service.ConstructionPreprocess(context.Background(),
+		&types.ConstructionPreprocessRequest{
+			Operations: operations,
+			Metadata:   objectsMap{"guardian": testscommon.TestAddressCarol},
+		},
+	)
+	require.Nil(t, preprocessResponse)
+	require.Equal(t, ErrGuardedTransactionsNotSupported, errCode(err.Code))
+
+	// The guardian fields would be silently dropped when decoding the transaction, thus they are explicitly rejected
+	signedTx := "{\"nonce\":42,\"value\":\"1234\",\"receiver\":\"erd1spyavw0956vq68xj8y4tenjpq2wd5a9p2c6j8gsz7ztyrnpxrruqzu66jx\",\"sender\":\"erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th\",\"gasPrice\":1000000000,\"gasLimit\":100000,\"signature\":\"aabb\",\"chainID\":\"T\",\"version\":2,\"options\":2,\"guardian\":\"erd1k2s324ww2g0yj38qn2ch2jwctdy8mnfxep94q9arncc6xecg3xaq6mjse8\",\"guardianSignature\":\"ccdd\"}"
+
+	hashResponse, err := service.ConstructionHash(context.Background(),
+		&types.ConstructionHashRequest{
+			SignedTransaction: signedTx,
+		},
+	)
+	require.Nil(t, hashResponse)
+	require.Equal(t, ErrGuardedTransactionsNotSupported, errCode(err.Code))
+
+	submitResponse, err := service.ConstructionSubmit(context.Background(),
+		&types.ConstructionSubmitRequest{
+			SignedTransaction: signedTx,
+		},
+	)
+	require.Nil(t, submitResponse)
+	require.Equal(t, ErrGuardedTransactionsNotSupported, errCode(err.Code))
+}
+
+func TestConstructionService_SignByHash(t *testing.T) {
+	networkProvider := testscommon.NewNetworkProviderMock()
+	networkProvider.MockNetworkConfig.ChainID = "T"
+	networkProvider.MockAccountsByAddress[testscommon.TestAddressAlice] = &data.Account{Address: testscommon.TestAddressAlice, Nonce: 42}
+
+	extension := newNetworkProviderExtension(networkProvider)
+	service := NewConstructionService(networkProvider)
+
+	operations := []*types.Operation{
+		{
+			OperationIdentifier: indexToOperationIdentifier(0),
+			Type:                opTransfer,
+			Account:             addressToAccountIdentifier(testscommon.TestAddressAlice),
+			Amount:              extension.valueToNativeAmount("-1234"),
+		},
+		{
+			OperationIdentifier: indexToOperationIdentifier(1),
+			Type:                opTransfer,
+			Account:             addressToAccountIdentifier(testscommon.TestAddressBob),
+			Amount:              extension.valueToNativeAmount("1234"),
+		},
+	}
+
+	preprocessResponse, err := service.ConstructionPreprocess(context.Background(),
+		&types.ConstructionPreprocessRequest{
+			Operations: operations,
+			Metadata:   objectsMap{"signByHash": true},
+		},
+	)
+	require.Nil(t, err)
+	require.Equal(t, true, preprocessResponse.Options["signByHash"])
+
+	metadataResponse, err := service.ConstructionMetadata(context.Background(),
+		&types.ConstructionMetadataRequest{
+			Options: preprocessResponse.Options,
+		},
+	)
+	require.Nil(t, err)
+	require.Equal(t, 2, metadataResponse.Metadata["version"])
+	require.Equal(t, 1, metadataResponse.Metadata["options"])
+
+	payloadsResponse, err := service.ConstructionPayloads(context.Background(),
+		&types.ConstructionPayloadsRequest{
+			Operations: operations,
+			Metadata:   metadataResponse.Metadata,
+		},
+	)
+	require.Nil(t, err)
+
+	unsignedTx := "{\"nonce\":42,\"value\":\"1234\",\"receiver\":\"erd1spyavw0956vq68xj8y4tenjpq2wd5a9p2c6j8gsz7ztyrnpxrruqzu66jx\",\"sender\":\"erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th\",\"gasPrice\":1000000000,\"gasLimit\":50000,\"chainID\":\"T\",\"version\":2,\"options\":1}"
+	require.Equal(t, unsignedTx, payloadsResponse.UnsignedTransaction)
+	// The payload to sign is the (keccak) hash of the serialized transaction
+	require.Equal(t, keccak.NewKeccak().Compute(unsignedTx), payloadsResponse.Payloads[0].Bytes)
+	require.Len(t, payloadsResponse.Payloads[0].Bytes, 32)
+
+	combineResponse, err := service.ConstructionCombine(context.Background(),
+		&types.ConstructionCombineRequest{
+			UnsignedTransaction: payloadsResponse.UnsignedTransaction,
+			Signatures: []*types.Signature{
+				newTestSignature(payloadsResponse.Payloads[0], testscommon.TestSecretKeyAlice),
+			},
+		},
+	)
+	require.Nil(t, err)
+
+	signedTx, _ := getTxFromRequest(combineResponse.SignedTransaction)
+	require.Equal(t, uint32(2), signedTx.Version)
+	require.Equal(t, uint32(1), signedTx.Options)
+	require.Len(t, signedTx.Signature, 128)
+}
+
+func newTestSignature(signingPayload *types.SigningPayload, secretKey []byte) *types.Signature {
+	return &types.Signature{
+		SigningPayload: signingPayload,
+		PublicKey: &types.PublicKey{
+			Bytes:     testscommon.GetPublicKeyOfTestSecretKey(secretKey),
+			CurveType: types.Edwards25519,
+		},
+		SignatureType: types.Ed25519,
+		Bytes:         testscommon.SignWithTestSecretKey(secretKey, signingPayload.Bytes),
+	}
+}
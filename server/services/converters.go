@@ -27,6 +27,13 @@ func blockSummaryToIdentifier(blockSummary *resources.BlockSummary) *types.Block
 	}
 }
 
+func customCurrencyToRosettaCurrency(currency resources.CustomCurrency) *types.Currency {
+	return &types.Currency{
+		Symbol:   currency.Symbol,
+		Decimals: currency.Decimals,
+	}
+}
+
 func addressToAccountIdentifier(address string) *types.AccountIdentifier {
 	return &types.AccountIdentifier{
 		Address: address,
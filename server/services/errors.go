@@ -28,6 +28,15 @@ const (
 	ErrInvalidInputParam
 	ErrOfflineMode
 	ErrUnableToGetGenesisBlock
+	ErrUnsupportedCurrency
+	ErrTransactionIsNotInBlock
+	ErrUnableToGetMempool
+	ErrInvalidSignature
+	ErrMaxFeeExceeded
+	ErrUnableToSimulateTransaction
+	ErrTransactionSimulationFailed
+	ErrUnsupportedSubNetwork
+	ErrGuardedTransactionsNotSupported
 )
 
 type errPrototype struct {
@@ -146,6 +155,51 @@ func createErrPrototypes() ([]errPrototype, map[errCode]errPrototype) {
 			message:   "unable to get genesis block",
 			retriable: true,
 		},
+		{
+			code:      ErrUnsupportedCurrency,
+			message:   "unsupported currency",
+			retriable: false,
+		},
+		{
+			code:      ErrTransactionIsNotInBlock,
+			message:   "transaction is not in block",
+			retriable: false,
+		},
+		{
+			code:      ErrUnableToGetMempool,
+			message:   "unable to get mempool",
+			retriable: true,
+		},
+		{
+			code:      ErrInvalidSignature,
+			message:   "invalid signature",
+			retriable: false,
+		},
+		{
+			code:      ErrMaxFeeExceeded,
+			message:   "fee would exceed max fee",
+			retriable: false,
+		},
+		{
+			code:      ErrUnableToSimulateTransaction,
+			message:   "unable to simulate transaction",
+			retriable: true,
+		},
+		{
+			code:      ErrTransactionSimulationFailed,
+			message:   "transaction simulation failed",
+			retriable: false,
+		},
+		{
+			code:      ErrUnsupportedSubNetwork,
+			message:   "unsupported sub-network",
+			retriable: false,
+		},
+		{
+			code:      ErrGuardedTransactionsNotSupported,
+			message:   "guarded transactions are not supported",
+			retriable: false,
+		},
 	}
 
 	prototypesMap := make(map[errCode]errPrototype)
@@ -190,6 +244,15 @@ func (factory *errFactory) newErr(code errCode) *types.Error {
 	}
 }
 
+// newErrOfTxFromRequest converts an error returned by getTxFromRequest into a typed error
+func (factory *errFactory) newErrOfTxFromRequest(err error) *types.Error {
+	if err == errGuardedTransaction {
+		return factory.newErr(ErrGuardedTransactionsNotSupported)
+	}
+
+	return factory.newErrWithOriginal(ErrMalformedValue, err)
+}
+
 func (factory *errFactory) getPrototypeByCode(code errCode) errPrototype {
 	prototype, ok := factory.prototypesMap[code]
 	if ok {
@@ -200,4 +263,6 @@ func (factory *errFactory) getPrototypeByCode(code errCode) errPrototype {
 }
 
 var errEventNotFound = errors.New("transaction event not found")
+var errBlockMismatch = errors.New("block mismatch")
+var errGuardedTransaction = errors.New("transaction has guardian fields")
 var errCannotRecognizeEvent = errors.New("cannot recognize transaction event")
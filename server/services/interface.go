@@ -13,21 +13,37 @@ type NetworkProvider interface {
 	GetBlockchainName() string
 	GetChainID() string
 	GetNativeCurrency() resources.NativeCurrency
-	GetObserverPubkey() string
+	GetCustomCurrencies() []resources.CustomCurrency
+	GetCustomCurrencyBySymbol(symbol string) (resources.CustomCurrency, bool)
+	GetCustomCurrencyByIdentifier(identifier string) (resources.CustomCurrency, bool)
+	GetMaxInlinedTransactions() uint64
+	GetGasLimitSafetyMargin() uint64
+	IsHistoricalBalanceLookupEnabled() bool
 	GetNetworkConfig() *resources.NetworkConfig
 	GetGenesisBlockSummary() *resources.BlockSummary
 	GetGenesisTimestamp() int64
 	GetGenesisBalances() ([]*resources.GenesisBalance, error)
 	GetLatestBlockSummary() (*resources.BlockSummary, error)
+	GetNodeStatusAndLatestBlockSummary() (*resources.NodeStatus, *resources.BlockSummary, error)
+	GetPeers() ([]data.PubKeyHeartbeat, error)
 	GetBlockByNonce(nonce uint64) (*data.Block, error)
 	GetBlockByHash(hash string) (*data.Block, error)
-	GetAccount(address string) (*data.AccountModel, error)
+	GetAccount(address string, options resources.AccountQueryOptions) (*data.AccountModel, error)
+	GetAccountESDTBalance(address string, tokenIdentifier string, options resources.AccountQueryOptions) (*resources.AccountESDTBalance, error)
+	GetAccountAllESDTBalances(address string, options resources.AccountQueryOptions) (map[string]*resources.AccountESDTBalance, error)
 	IsAddressObserved(address string) (bool, error)
+	GetObservedActualShard() uint32
+	ComputeShardIdOfAddress(address string) (uint32, error)
+	IsContractAddressTracked(address string) bool
 	ConvertPubKeyToAddress(pubkey []byte) string
 	ConvertAddressToPubKey(address string) ([]byte, error)
 	SendTransaction(tx *data.Transaction) (string, error)
+	SimulateTransactionCost(tx *data.Transaction) (*data.TxCostResponseData, error)
 	ComputeTransactionHash(tx *data.Transaction) (string, error)
+	ComputeTransactionSigningPayload(tx *data.Transaction) ([]byte, error)
 	ComputeReceiptHash(apiReceipt *transaction.ApiReceipt) (string, error)
 	ComputeTransactionFeeForMoveBalance(tx *data.FullTransaction) *big.Int
+	ComputeTransactionFee(tx *data.FullTransaction) *big.Int
 	GetMempoolTransactionByHash(hash string) (*data.FullTransaction, error)
+	GetMempoolTransactions() ([]*resources.TransactionInPool, error)
 }
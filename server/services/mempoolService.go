@@ -2,7 +2,10 @@ package services
 
 import (
 	"context"
+	"errors"
 
+	"github.com/ElrondNetwork/elrond-proxy-go/data"
+	"github.com/ElrondNetwork/rosetta/server/resources"
 	"github.com/coinbase/rosetta-sdk-go/server"
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
@@ -22,9 +25,102 @@ func NewMempoolService(provider NetworkProvider) server.MempoolAPIServicer {
 	}
 }
 
-// Mempool is not implemented yet
-func (service *mempoolService) Mempool(context.Context, *types.NetworkRequest) (*types.MempoolResponse, *types.Error) {
-	return nil, newErrFactory().newErr(ErrNotImplemented)
+// Mempool implements the /mempool endpoint. It lists the pending transactions having operations of interest (i.e. the same operations
+// that would be reported by /mempool/transaction and, eventually, by /block). Optionally, the listing can be restricted to the transactions
+// having operations that affect a given set of addresses, specified as "metadata.addresses" (e.g. for watching pending deposits).
+func (service *mempoolService) Mempool(_ context.Context, request *types.NetworkRequest) (*types.MempoolResponse, *types.Error) {
+	addresses, errTyped := service.getAddressesOfInterest(request.Metadata)
+	if errTyped != nil {
+		return nil, errTyped
+	}
+
+	txs, err := service.provider.GetMempoolTransactions()
+	if err != nil {
+		return nil, service.errFactory.newErrWithOriginal(ErrUnableToGetMempool, err)
+	}
+
+	// Same as for /mempool/transaction, the activation of custom currencies is checked against the latest block.
+	latestBlockSummary, err := service.provider.GetLatestBlockSummary()
+	if err != nil {
+		return nil, service.errFactory.newErrWithOriginal(ErrUnableToGetBlock, err)
+	}
+
+	identifiers := make([]*types.TransactionIdentifier, 0, len(txs))
+
+	for _, tx := range txs {
+		isOfInterest, err := service.isMempoolTransactionOfInterest(tx, addresses, latestBlockSummary.Nonce)
+		if err != nil {
+			return nil, service.errFactory.newErrWithOriginal(ErrUnableToGetMempool, err)
+		}
+
+		if isOfInterest {
+			identifiers = append(identifiers, hashToTransactionIdentifier(tx.Hash))
+		}
+	}
+
+	return &types.MempoolResponse{
+		TransactionIdentifiers: identifiers,
+	}, nil
+}
+
+// getAddressesOfInterest returns the addresses specified in the request metadata (or nil, if none are specified)
+func (service *mempoolService) getAddressesOfInterest(metadata objectsMap) (map[string]struct{}, *types.Error) {
+	addressesI, ok := metadata["addresses"]
+	if !ok {
+		return nil, nil
+	}
+
+	addressesSlice, ok := addressesI.([]interface{})
+	if !ok {
+		return nil, service.errFactory.newErrWithOriginal(ErrInvalidInputParam, errors.New("addresses must be a list"))
+	}
+
+	addresses := make(map[string]struct{}, len(addressesSlice))
+
+	for _, addressI := range addressesSlice {
+		address, ok := addressI.(string)
+		if !ok {
+			return nil, service.errFactory.newErrWithOriginal(ErrInvalidInputParam, errors.New("address is invalid"))
+		}
+
+		addresses[address] = struct{}{}
+	}
+
+	return addresses, nil
+}
+
+// isMempoolTransactionOfInterest tells whether a pending transaction has operations of interest (i.e. affecting observed and tracked accounts).
+// The transaction is transformed as in /mempool/transaction, so that the actual senders and receivers are considered (e.g. the destination
+// of a "MultiESDTNFTTransfer", which is held in the data field, or the inner receiver of a relayed transaction).
+func (service *mempoolService) isMempoolTransactionOfInterest(tx *resources.TransactionInPool, addresses map[string]struct{}, blockNonce uint64) (bool, error) {
+	rosettaTx, err := service.txsTransformer.mempoolTxToRosettaTx(poolTransactionToFullTransaction(tx), blockNonce)
+	if err != nil {
+		return false, err
+	}
+
+	if addresses == nil {
+		return len(rosettaTx.Operations) > 0, nil
+	}
+
+	for _, operation := range rosettaTx.Operations {
+		if _, ok := addresses[operation.Account.Address]; ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func poolTransactionToFullTransaction(tx *resources.TransactionInPool) *data.FullTransaction {
+	return &data.FullTransaction{
+		Hash:     tx.Hash,
+		Sender:   tx.Sender,
+		Receiver: tx.Receiver,
+		Value:    tx.Value,
+		Data:     tx.Data,
+		GasLimit: tx.GasLimit,
+		GasPrice: tx.GasPrice,
+	}
 }
 
 // MempoolTransaction will return operations for a transaction that is in pool
@@ -40,9 +136,15 @@ func (service *mempoolService) MempoolTransaction(
 		return nil, service.errFactory.newErr(ErrTransactionIsNotInPool)
 	}
 
-	rosettaTx := service.txsTransformer.mempoolMoveBalanceTxToRosettaTx(tx)
+	// The transaction is going to be included in a block after the latest one (thus, we check the activation of custom currencies against the latter).
+	latestBlockSummary, err := service.provider.GetLatestBlockSummary()
 	if err != nil {
-		return nil, service.errFactory.newErr(ErrCannotParsePoolTransaction)
+		return nil, service.errFactory.newErrWithOriginal(ErrUnableToGetBlock, err)
+	}
+
+	rosettaTx, err := service.txsTransformer.mempoolTxToRosettaTx(tx, latestBlockSummary.Nonce)
+	if err != nil {
+		return nil, service.errFactory.newErrWithOriginal(ErrCannotParsePoolTransaction, err)
 	}
 
 	return &types.MempoolTransactionResponse{
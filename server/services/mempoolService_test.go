@@ -2,10 +2,12 @@ package services
 
 import (
 	"context"
+	"encoding/hex"
 	"testing"
 
 	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
 	"github.com/ElrondNetwork/elrond-proxy-go/data"
+	"github.com/ElrondNetwork/rosetta/server/resources"
 	"github.com/ElrondNetwork/rosetta/testscommon"
 	"github.com/coinbase/rosetta-sdk-go/server"
 	"github.com/coinbase/rosetta-sdk-go/types"
@@ -23,6 +25,7 @@ func TestMempoolService_MempoolTransactionCannotFindTxInPool(t *testing.T) {
 
 func TestMempoolService_MempoolTransaction(t *testing.T) {
 	networkProvider := testscommon.NewNetworkProviderMock()
+	networkProvider.MockNumShards = 1
 	extension := newNetworkProviderExtension(networkProvider)
 	service := NewMempoolService(networkProvider)
 
@@ -51,6 +54,12 @@ func TestMempoolService_MempoolTransaction(t *testing.T) {
 				Account:             addressToAccountIdentifier(testscommon.TestAddressBob),
 				Amount:              extension.valueToNativeAmount("1234"),
 			},
+			{
+				OperationIdentifier: indexToOperationIdentifier(2),
+				Type:                opFee,
+				Account:             addressToAccountIdentifier(testscommon.TestAddressAlice),
+				Amount:              extension.valueToNativeAmount("-50000000000000"),
+			},
 		},
 	}
 
@@ -59,6 +68,235 @@ func TestMempoolService_MempoolTransaction(t *testing.T) {
 	require.Equal(t, expectedRosettaTx, txResponse.Transaction)
 }
 
+func TestMempoolService_MempoolTransactionWithTokenTransfers(t *testing.T) {
+	networkProvider := testscommon.NewNetworkProviderMock()
+	networkProvider.MockNumShards = 1
+	networkProvider.MockCustomCurrencies = []resources.CustomCurrency{
+		{Identifier: "ROSETTA-3a2edf", Symbol: "ROSETTA", Decimals: 2},
+		{Identifier: "EXAMPLE-453bec", Symbol: "EXAMPLE", Decimals: 4},
+		{Identifier: "FUTURE-abcdef", Symbol: "FUTURE", Decimals: 0, ActivationNonce: 1000},
+	}
+
+	extension := newNetworkProviderExtension(networkProvider)
+	service := NewMempoolService(networkProvider)
+
+	rosetta := networkProvider.MockCustomCurrencies[0]
+	example := networkProvider.MockCustomCurrencies[1]
+
+	t.Run("ESDTTransfer, with contract call", func(t *testing.T) {
+		// ESDTTransfer@ROSETTA-3a2edf@100@deposit
+		networkProvider.MockMempoolTransactionsByHash["aaaa"] = &data.FullTransaction{
+			Hash:     "aaaa",
+			Type:     string(transaction.TxTypeNormal),
+			Sender:   testscommon.TestAddressAlice,
+			Receiver: testscommon.TestAddressBob,
+			Value:    "0",
+			Data:     []byte("ESDTTransfer@524f53455454412d336132656466@64@6465706f736974"),
+			GasLimit: 600000,
+			GasPrice: 1000000000,
+		}
+
+		expectedOperations := []*types.Operation{
+			{
+				OperationIdentifier: indexToOperationIdentifier(0),
+				Type:                opFee,
+				Account:             addressToAccountIdentifier(testscommon.TestAddressAlice),
+				Amount:              extension.valueToNativeAmount("-143115000000000"),
+			},
+			{
+				OperationIdentifier: indexToOperationIdentifier(1),
+				Type:                opTransfer,
+				Account:             addressToAccountIdentifier(testscommon.TestAddressAlice),
+				Amount:              extension.valueToCustomAmount("-100", rosetta),
+			},
+			{
+				OperationIdentifier: indexToOperationIdentifier(2),
+				Type:                opTransfer,
+				Account:             addressToAccountIdentifier(testscommon.TestAddressBob),
+				Amount:              extension.valueToCustomAmount("100", rosetta),
+			},
+		}
+
+		txResponse, err := getMempoolTransactionByHash(service, "aaaa")
+		require.Nil(t, err)
+		require.Equal(t, expectedOperations, txResponse.Transaction.Operations)
+	})
+
+	t.Run("MultiESDTNFTTransfer, with an unsupported (not yet active) token", func(t *testing.T) {
+		// MultiESDTNFTTransfer@Bob@3@ROSETTA-3a2edf@0@100@EXAMPLE-453bec@0@200@FUTURE-abcdef@0@300
+		networkProvider.MockMempoolTransactionsByHash["bbbb"] = &data.FullTransaction{
+			Hash:     "bbbb",
+			Type:     string(transaction.TxTypeNormal),
+			Sender:   testscommon.TestAddressAlice,
+			Receiver: testscommon.TestAddressAlice,
+			Value:    "0",
+			Data: []byte("MultiESDTNFTTransfer@" + testscommon.TestPubKeyHexBob + "@03" +
+				"@524f53455454412d336132656466@00@64" +
+				"@4558414d504c452d343533626563@00@c8" +
+				"@4655545552452d616263646566@00@012c"),
+			GasLimit: 50000,
+			GasPrice: 1000000000,
+		}
+
+		txResponse, err := getMempoolTransactionByHash(service, "bbbb")
+		require.Nil(t, err)
+
+		operations := txResponse.Transaction.Operations
+		require.Len(t, operations, 5)
+		require.Equal(t, opFee, operations[0].Type)
+		require.Equal(t, extension.valueToCustomAmount("-100", rosetta), operations[1].Amount)
+		require.Equal(t, testscommon.TestAddressBob, operations[2].Account.Address)
+		require.Equal(t, extension.valueToCustomAmount("100", rosetta), operations[2].Amount)
+		require.Equal(t, extension.valueToCustomAmount("-200", example), operations[3].Amount)
+		require.Equal(t, testscommon.TestAddressBob, operations[4].Account.Address)
+		require.Equal(t, extension.valueToCustomAmount("200", example), operations[4].Amount)
+	})
+
+	t.Run("relayed transaction (v2), holding an ESDTTransfer", func(t *testing.T) {
+		// relayedTxV2@Alice@nonce@hex("ESDTTransfer@EXAMPLE-453bec@0a")@signature, relayed by the contract (as a stand-in for any relayer)
+		innerData := hex.EncodeToString([]byte("ESDTTransfer@4558414d504c452d343533626563@0a"))
+
+		networkProvider.MockMempoolTransactionsByHash["cccc"] = &data.FullTransaction{
+			Hash:     "cccc",
+			Type:     string(transaction.TxTypeNormal),
+			Sender:   testscommon.TestAddressOfContract,
+			Receiver: testscommon.TestAddressBob,
+			Value:    "0",
+			Data:     []byte("relayedTxV2@" + testscommon.TestPubKeyHexAlice + "@07@" + innerData + "@abcd"),
+			GasLimit: 50000,
+			GasPrice: 1000000000,
+		}
+
+		txResponse, err := getMempoolTransactionByHash(service, "cccc")
+		require.Nil(t, err)
+
+		// The fee operation of the relayer (a contract, thus not a user address) is discarded.
+		operations := txResponse.Transaction.Operations
+		require.Len(t, operations, 2)
+		require.Equal(t, testscommon.TestAddressBob, operations[0].Account.Address)
+		require.Equal(t, extension.valueToCustomAmount("-10", example), operations[0].Amount)
+		require.Equal(t, testscommon.TestAddressAlice, operations[1].Account.Address)
+		require.Equal(t, extension.valueToCustomAmount("10", example), operations[1].Amount)
+		require.Nil(t, operations[0].Status)
+		require.Nil(t, operations[1].Status)
+	})
+}
+
+func TestMempoolService_Mempool(t *testing.T) {
+	networkProvider := testscommon.NewNetworkProviderMock()
+	networkProvider.MockCustomCurrencies = []resources.CustomCurrency{{Identifier: "ROSETTA-3a2edf", Symbol: "ROSETTA", Decimals: 2}}
+	service := NewMempoolService(networkProvider)
+
+	networkProvider.MockMempoolTransactionsByHash["aaaa"] = &data.FullTransaction{
+		Hash:     "aaaa",
+		Sender:   testscommon.TestAddressAlice,
+		Receiver: testscommon.TestAddressBob,
+		Value:    "1",
+		GasLimit: 50000,
+		GasPrice: 1000000000,
+	}
+
+	networkProvider.MockMempoolTransactionsByHash["bbbb"] = &data.FullTransaction{
+		Hash:     "bbbb",
+		Sender:   testscommon.TestAddressBob,
+		Receiver: testscommon.TestAddressOfContract,
+		Value:    "1",
+		GasLimit: 50000,
+		GasPrice: 1000000000,
+	}
+
+	networkProvider.MockMempoolTransactionsByHash["cccc"] = &data.FullTransaction{
+		Hash:     "cccc",
+		Sender:   testscommon.TestAddressBob,
+		Receiver: testscommon.TestAddressBob,
+		Value:    "0",
+		GasLimit: 50000,
+		GasPrice: 1000000000,
+	}
+
+	// ESDTNFTTransfer@ROSETTA-3a2edf@0@100@Bob (the receiver of the transaction is the sender itself)
+	networkProvider.MockMempoolTransactionsByHash["dddd"] = &data.FullTransaction{
+		Hash:     "dddd",
+		Sender:   testscommon.TestAddressAlice,
+		Receiver: testscommon.TestAddressAlice,
+		Value:    "0",
+		Data:     []byte("ESDTNFTTransfer@524f53455454412d336132656466@00@64@" + testscommon.TestPubKeyHexBob),
+		GasLimit: 500000,
+		GasPrice: 1000000000,
+	}
+
+	t.Run("when observing shard of Alice", func(t *testing.T) {
+		networkProvider.MockObservedActualShard = 1
+
+		response, err := getMempool(service, nil)
+		require.Nil(t, err)
+		require.ElementsMatch(t, []*types.TransactionIdentifier{
+			hashToTransactionIdentifier("aaaa"),
+			hashToTransactionIdentifier("dddd"),
+		}, response.TransactionIdentifiers)
+	})
+
+	t.Run("when observing shard of Bob", func(t *testing.T) {
+		networkProvider.MockObservedActualShard = 0
+
+		response, err := getMempool(service, nil)
+		require.Nil(t, err)
+		require.ElementsMatch(t, []*types.TransactionIdentifier{
+			hashToTransactionIdentifier("aaaa"),
+			hashToTransactionIdentifier("bbbb"),
+			hashToTransactionIdentifier("cccc"),
+			hashToTransactionIdentifier("dddd"),
+		}, response.TransactionIdentifiers)
+	})
+
+	t.Run("with addresses of interest", func(t *testing.T) {
+		networkProvider.MockObservedActualShard = 0
+
+		response, err := getMempool(service, objectsMap{
+			"addresses": []interface{}{testscommon.TestAddressOfContract},
+		})
+		require.Nil(t, err)
+		require.Empty(t, response.TransactionIdentifiers)
+
+		// Same as for /block, the operations of contracts are reported only if the contracts are tracked
+		networkProvider.MockTrackedContracts[testscommon.TestAddressOfContract] = struct{}{}
+
+		response, err = getMempool(service, objectsMap{
+			"addresses": []interface{}{testscommon.TestAddressOfContract},
+		})
+		require.Nil(t, err)
+		require.ElementsMatch(t, []*types.TransactionIdentifier{
+			hashToTransactionIdentifier("bbbb"),
+		}, response.TransactionIdentifiers)
+
+		// The actual receiver of an "ESDTNFTTransfer" is held in the data field
+		response, err = getMempool(service, objectsMap{
+			"addresses": []interface{}{testscommon.TestAddressBob},
+		})
+		require.Nil(t, err)
+		require.ElementsMatch(t, []*types.TransactionIdentifier{
+			hashToTransactionIdentifier("aaaa"),
+			hashToTransactionIdentifier("bbbb"),
+			hashToTransactionIdentifier("cccc"),
+			hashToTransactionIdentifier("dddd"),
+		}, response.TransactionIdentifiers)
+	})
+
+	t.Run("with bad addresses of interest", func(t *testing.T) {
+		response, err := getMempool(service, objectsMap{
+			"addresses": testscommon.TestAddressOfContract,
+		})
+		require.Equal(t, ErrInvalidInputParam, errCode(err.Code))
+		require.Nil(t, response)
+	})
+}
+
+func getMempool(service server.MempoolAPIServicer, metadata objectsMap) (*types.MempoolResponse, *types.Error) {
+	return service.Mempool(context.Background(), &types.NetworkRequest{
+		Metadata: metadata,
+	})
+}
+
 func getMempoolTransactionByHash(service server.MempoolAPIServicer, hash string) (*types.MempoolTransactionResponse, *types.Error) {
 	return service.MempoolTransaction(context.Background(), &types.MempoolTransactionRequest{
 		NetworkIdentifier:     nil,
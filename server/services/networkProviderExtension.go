@@ -2,6 +2,7 @@ package services
 
 import (
 	"github.com/ElrondNetwork/elrond-go-core/core"
+	"github.com/ElrondNetwork/rosetta/server/resources"
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
@@ -31,6 +32,41 @@ func (extension *networkProviderExtension) getNativeCurrency() *types.Currency {
 	}
 }
 
+func (extension *networkProviderExtension) valueToCustomAmount(value string, currency resources.CustomCurrency) *types.Amount {
+	return &types.Amount{
+		Value:    value,
+		Currency: customCurrencyToRosettaCurrency(currency),
+	}
+}
+
+// getActiveCustomCurrencyBySymbol returns a custom currency of the registry, if it's active at the given block
+func (extension *networkProviderExtension) getActiveCustomCurrencyBySymbol(symbol string, blockNonce uint64) (resources.CustomCurrency, bool) {
+	currency, ok := extension.provider.GetCustomCurrencyBySymbol(symbol)
+	if !ok || !isCustomCurrencyActive(currency, blockNonce) {
+		return resources.CustomCurrency{}, false
+	}
+
+	return currency, true
+}
+
+// getActiveCustomCurrencyByIdentifier returns a custom currency of the registry, if it's active at the given block
+func (extension *networkProviderExtension) getActiveCustomCurrencyByIdentifier(identifier string, blockNonce uint64) (resources.CustomCurrency, bool) {
+	currency, ok := extension.provider.GetCustomCurrencyByIdentifier(identifier)
+	if !ok || !isCustomCurrencyActive(currency, blockNonce) {
+		return resources.CustomCurrency{}, false
+	}
+
+	return currency, true
+}
+
+func isCustomCurrencyActive(currency resources.CustomCurrency, blockNonce uint64) bool {
+	return blockNonce >= currency.ActivationNonce
+}
+
+func (extension *networkProviderExtension) isNativeCurrencySymbol(symbol string) bool {
+	return symbol == extension.provider.GetNativeCurrency().Symbol
+}
+
 func (extension *networkProviderExtension) isNativeCurrency(currency *types.Currency) bool {
 	nativeCurrency := extension.provider.GetNativeCurrency()
 	return currency.Symbol == nativeCurrency.Symbol && currency.Decimals == nativeCurrency.Decimals
@@ -52,9 +88,9 @@ func (extension *networkProviderExtension) filterObservedOperations(operations [
 			return nil, err
 		}
 
-		isUserAddress := extension.isUserAddress(address)
+		isTrackedAddress := extension.isUserAddress(address) || extension.provider.IsContractAddressTracked(address)
 
-		if isObserved && isUserAddress {
+		if isObserved && isTrackedAddress {
 			filtered = append(filtered, operation)
 		}
 	}
@@ -2,7 +2,9 @@ package services
 
 import (
 	"context"
+	"strings"
 
+	"github.com/ElrondNetwork/rosetta/server/resources"
 	"github.com/ElrondNetwork/rosetta/version"
 	"github.com/coinbase/rosetta-sdk-go/server"
 	"github.com/coinbase/rosetta-sdk-go/types"
@@ -49,25 +51,89 @@ func (service *networkService) NetworkStatus(
 		return nil, service.errFactory.newErr(ErrOfflineMode)
 	}
 
-	latestBlockSummary, err := service.provider.GetLatestBlockSummary()
+	// The current block and the sync status are derived from the same node status (fetched once).
+	nodeStatus, latestBlockSummary, err := service.provider.GetNodeStatusAndLatestBlockSummary()
 	if err != nil {
-		return nil, service.errFactory.newErrWithOriginal(ErrUnableToGetBlock, err)
+		return nil, service.errFactory.newErrWithOriginal(ErrUnableToGetNodeStatus, err)
 	}
 
 	networkStatusResponse := &types.NetworkStatusResponse{
+		SyncStatus:             nodeStatusToSyncStatus(nodeStatus),
 		CurrentBlockIdentifier: blockSummaryToIdentifier(latestBlockSummary),
 		CurrentBlockTimestamp:  timestampInMilliseconds(latestBlockSummary.Timestamp),
 		GenesisBlockIdentifier: service.extension.getGenesisBlockIdentifier(),
-		Peers: []*types.Peer{
-			{
-				PeerID: service.provider.GetObserverPubkey(),
-			},
-		},
+		Peers:                  service.getPeers(nodeStatus),
 	}
 
 	return networkStatusResponse, nil
 }
 
+// getPeers returns the observer itself, followed by the (active) peers it knows of. If the peers cannot be fetched
+// (e.g. the heartbeat status isn't available), only the observer is returned.
+func (service *networkService) getPeers(nodeStatus *resources.NodeStatus) []*types.Peer {
+	observerPubkey := nodeStatus.PublicKeyBlockSign
+	observer := &types.Peer{
+		PeerID: observerPubkey,
+		Metadata: objectsMap{
+			"shard":       nodeStatus.ShardID,
+			"peerType":    nodeStatus.PeerType,
+			"version":     nodeStatus.AppVersion,
+			"displayName": nodeStatus.NodeDisplayName,
+		},
+	}
+
+	heartbeats, err := service.provider.GetPeers()
+	if err != nil {
+		log.Debug("networkService.getPeers(): cannot get peers", "err", err)
+		return []*types.Peer{observer}
+	}
+
+	peers := make([]*types.Peer, 0, len(heartbeats)+1)
+	peers = append(peers, observer)
+
+	for _, heartbeat := range heartbeats {
+		if strings.EqualFold(heartbeat.PublicKey, observerPubkey) {
+			continue
+		}
+
+		peers = append(peers, &types.Peer{
+			PeerID: heartbeat.PublicKey,
+			Metadata: objectsMap{
+				"shard":       heartbeat.ComputedShardID,
+				"peerType":    heartbeat.PeerType,
+				"version":     heartbeat.VersionNumber,
+				"displayName": heartbeat.NodeDisplayName,
+				"identity":    heartbeat.Identity,
+			},
+		})
+	}
+
+	return peers
+}
+
+// nodeStatusToSyncStatus describes the synchronization of the observer: the last synchronized block (not necessarily final, thus
+// possibly above the current block), versus the highest block known to the network (as perceived by the observer).
+func nodeStatusToSyncStatus(nodeStatus *resources.NodeStatus) *types.SyncStatus {
+	currentIndex := int64(nodeStatus.HighestNonce)
+	targetIndex := int64(nodeStatus.ProbableHighestNonce)
+	if targetIndex < currentIndex {
+		targetIndex = currentIndex
+	}
+
+	synced := nodeStatus.IsSyncing == 0
+	stage := syncStageSynced
+	if !synced {
+		stage = syncStageSyncing
+	}
+
+	return &types.SyncStatus{
+		CurrentIndex: &currentIndex,
+		TargetIndex:  &targetIndex,
+		Stage:        &stage,
+		Synced:       &synced,
+	}
+}
+
 // NetworkOptions implements the /network/options endpoint.
 func (service *networkService) NetworkOptions(
 	_ context.Context,
@@ -77,11 +143,25 @@ func (service *networkService) NetworkOptions(
 		Version: &types.Version{
 			RosettaVersion: version.RosettaVersion,
 			NodeVersion:    version.NodeVersion,
+			Metadata:       service.getVersionMetadata(),
 		},
 		Allow: &types.Allow{
-			OperationStatuses: supportedOperationStatuses,
-			OperationTypes:    SupportedOperationTypes,
-			Errors:            service.errFactory.getPossibleErrors(),
+			OperationStatuses:       supportedOperationStatuses,
+			OperationTypes:          SupportedOperationTypes,
+			Errors:                  service.errFactory.getPossibleErrors(),
+			HistoricalBalanceLookup: service.provider.IsHistoricalBalanceLookupEnabled(),
 		},
 	}, nil
 }
+
+// getVersionMetadata advertises the registry of supported custom currencies (since "types.Allow" has no field to hold them)
+func (service *networkService) getVersionMetadata() objectsMap {
+	customCurrencies := service.provider.GetCustomCurrencies()
+	if len(customCurrencies) == 0 {
+		return nil
+	}
+
+	return objectsMap{
+		"customCurrencies": customCurrencies,
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 
+	"github.com/ElrondNetwork/elrond-proxy-go/data"
+	"github.com/ElrondNetwork/rosetta/server/resources"
 	"github.com/ElrondNetwork/rosetta/testscommon"
 	"github.com/ElrondNetwork/rosetta/version"
 	"github.com/coinbase/rosetta-sdk-go/types"
@@ -27,6 +29,7 @@ func TestNetworkService_NetworkList(t *testing.T) {
 func TestNetworkService_NetworkOptions(t *testing.T) {
 	networkProvider := testscommon.NewNetworkProviderMock()
 	networkProvider.MockNetworkConfig.ChainID = "T"
+	networkProvider.MockHistoricalBalanceLookup = true
 	service := NewNetworkService(networkProvider)
 
 	networkOptions, err := service.NetworkOptions(context.Background(), nil)
@@ -37,21 +40,42 @@ func TestNetworkService_NetworkOptions(t *testing.T) {
 			NodeVersion:    version.NodeVersion,
 		},
 		Allow: &types.Allow{
-			OperationStatuses: supportedOperationStatuses,
-			OperationTypes:    SupportedOperationTypes,
-			Errors:            newErrFactory().getPossibleErrors(),
+			OperationStatuses:       supportedOperationStatuses,
+			OperationTypes:          SupportedOperationTypes,
+			Errors:                  newErrFactory().getPossibleErrors(),
+			HistoricalBalanceLookup: true,
 		},
 	}, networkOptions)
 }
 
+func TestNetworkService_NetworkOptionsWithCustomCurrencies(t *testing.T) {
+	networkProvider := testscommon.NewNetworkProviderMock()
+	networkProvider.MockCustomCurrencies = []resources.CustomCurrency{
+		{Identifier: "ROSETTA-3a2edf", Symbol: "ROSETTA", Decimals: 2, ActivationNonce: 42},
+	}
+	service := NewNetworkService(networkProvider)
+
+	networkOptions, err := service.NetworkOptions(context.Background(), nil)
+	require.Nil(t, err)
+	require.Equal(t, map[string]interface{}{
+		"customCurrencies": networkProvider.MockCustomCurrencies,
+	}, networkOptions.Version.Metadata)
+}
+
 func TestNetworkService_NetworkStatus(t *testing.T) {
 	networkProvider := testscommon.NewNetworkProviderMock()
 	networkProvider.MockNetworkConfig.ChainID = "T"
-	networkProvider.MockObserverPubkey = "my-computer"
+	networkProvider.MockNodeStatus.PublicKeyBlockSign = "my-computer"
 	networkProvider.MockGenesisBlockHash = "genesisHash"
 	networkProvider.MockLatestBlockSummary.Nonce = 42
 	networkProvider.MockLatestBlockSummary.Hash = "latestHash"
 	networkProvider.MockLatestBlockSummary.Timestamp = 123456789
+	networkProvider.MockNodeStatus.HighestNonce = 43
+	networkProvider.MockNodeStatus.ProbableHighestNonce = 43
+	networkProvider.MockNodeStatus.ShardID = 1
+	networkProvider.MockNodeStatus.PeerType = "observer"
+	networkProvider.MockNodeStatus.AppVersion = "v1.3.27"
+	networkProvider.MockNodeStatus.NodeDisplayName = "my-observer"
 
 	service := NewNetworkService(networkProvider)
 
@@ -59,6 +83,12 @@ func TestNetworkService_NetworkStatus(t *testing.T) {
 
 	require.Nil(t, err)
 	require.Equal(t, &types.NetworkStatusResponse{
+		SyncStatus: &types.SyncStatus{
+			CurrentIndex: types.Int64(43),
+			TargetIndex:  types.Int64(43),
+			Stage:        types.String("synced"),
+			Synced:       types.Bool(true),
+		},
 		CurrentBlockIdentifier: &types.BlockIdentifier{
 			Index: 42,
 			Hash:  "latestHash",
@@ -71,7 +101,68 @@ func TestNetworkService_NetworkStatus(t *testing.T) {
 		Peers: []*types.Peer{
 			{
 				PeerID: "my-computer",
+				Metadata: map[string]interface{}{
+					"shard":       uint64(1),
+					"peerType":    "observer",
+					"version":     "v1.3.27",
+					"displayName": "my-observer",
+				},
 			},
 		},
 	}, networkStatusResponse)
 }
+
+func TestNetworkService_NetworkStatusWithPeers(t *testing.T) {
+	networkProvider := testscommon.NewNetworkProviderMock()
+	networkProvider.MockNodeStatus.PublicKeyBlockSign = "my-computer"
+	networkProvider.MockPeers = []data.PubKeyHeartbeat{
+		{PublicKey: "my-computer", ComputedShardID: 1, PeerType: "observer", IsActive: true},
+		{PublicKey: "alice-node", ComputedShardID: 0, PeerType: "eligible", VersionNumber: "v1.3.27", NodeDisplayName: "alice", Identity: "alice-staking", IsActive: true},
+		{PublicKey: "bob-node", ComputedShardID: 4294967295, PeerType: "waiting", VersionNumber: "v1.3.26", NodeDisplayName: "bob", IsActive: true},
+	}
+
+	service := NewNetworkService(networkProvider)
+
+	networkStatusResponse, err := service.NetworkStatus(context.Background(), nil)
+	require.Nil(t, err)
+
+	// The observer comes first (and only once)
+	require.Len(t, networkStatusResponse.Peers, 3)
+	require.Equal(t, "my-computer", networkStatusResponse.Peers[0].PeerID)
+	require.Equal(t, &types.Peer{
+		PeerID: "alice-node",
+		Metadata: map[string]interface{}{
+			"shard":       uint32(0),
+			"peerType":    "eligible",
+			"version":     "v1.3.27",
+			"displayName": "alice",
+			"identity":    "alice-staking",
+		},
+	}, networkStatusResponse.Peers[1])
+	require.Equal(t, "bob-node", networkStatusResponse.Peers[2].PeerID)
+	require.Equal(t, uint32(4294967295), networkStatusResponse.Peers[2].Metadata["shard"])
+}
+
+func TestNetworkService_NetworkStatusWhenSyncing(t *testing.T) {
+	networkProvider := testscommon.NewNetworkProviderMock()
+	networkProvider.MockNodeStatus.HighestNonce = 100
+	networkProvider.MockNodeStatus.ProbableHighestNonce = 250
+	networkProvider.MockNodeStatus.IsSyncing = 1
+
+	service := NewNetworkService(networkProvider)
+
+	networkStatusResponse, err := service.NetworkStatus(context.Background(), nil)
+	require.Nil(t, err)
+	require.Equal(t, &types.SyncStatus{
+		CurrentIndex: types.Int64(100),
+		TargetIndex:  types.Int64(250),
+		Stage:        types.String("syncing"),
+		Synced:       types.Bool(false),
+	}, networkStatusResponse.SyncStatus)
+
+	// The probable highest nonce might lag behind the current nonce (e.g. right after a restart)
+	networkProvider.MockNodeStatus.ProbableHighestNonce = 0
+	networkStatusResponse, err = service.NetworkStatus(context.Background(), nil)
+	require.Nil(t, err)
+	require.Equal(t, types.Int64(100), networkStatusResponse.SyncStatus.TargetIndex)
+}
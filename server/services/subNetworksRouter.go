@@ -0,0 +1,364 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/ElrondNetwork/elrond-go-core/core"
+	"github.com/coinbase/rosetta-sdk-go/server"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const subNetworkMetachain = "metachain"
+
+// subNetworkServices holds the services bound to the network provider of a single shard
+type subNetworkServices struct {
+	provider     NetworkProvider
+	network      server.NetworkAPIServicer
+	account      server.AccountAPIServicer
+	block        server.BlockAPIServicer
+	mempool      server.MempoolAPIServicer
+	construction server.ConstructionAPIServicer
+}
+
+// subNetworksRouter is used when a single instance observes multiple shards: each shard is exposed as a sub-network,
+// and requests are dispatched to the services of the corresponding shard.
+type subNetworksRouter struct {
+	subNetworks          []string
+	servicesBySubNetwork map[string]*subNetworkServices
+	errFactory           *errFactory
+}
+
+// NewSubNetworksRouter creates a new instance of subNetworksRouter, given the network providers of the observed shards (one per shard)
+func NewSubNetworksRouter(networkProviders []NetworkProvider) (*subNetworksRouter, error) {
+	if len(networkProviders) == 0 {
+		return nil, errors.New("no network provider given")
+	}
+
+	subNetworks := make([]string, 0, len(networkProviders))
+	servicesBySubNetwork := make(map[string]*subNetworkServices)
+
+	for _, provider := range networkProviders {
+		subNetwork := shardToSubNetwork(provider.GetObservedActualShard())
+		if _, exists := servicesBySubNetwork[subNetwork]; exists {
+			return nil, fmt.Errorf("shard observed more than once: %s", subNetwork)
+		}
+
+		subNetworks = append(subNetworks, subNetwork)
+		servicesBySubNetwork[subNetwork] = &subNetworkServices{
+			provider:     provider,
+			network:      NewNetworkService(provider),
+			account:      NewAccountService(provider),
+			block:        NewBlockService(provider),
+			mempool:      NewMempoolService(provider),
+			construction: NewConstructionService(provider),
+		}
+	}
+
+	return &subNetworksRouter{
+		subNetworks:          subNetworks,
+		servicesBySubNetwork: servicesBySubNetwork,
+		errFactory:           newErrFactory(),
+	}, nil
+}
+
+func shardToSubNetwork(shard uint32) string {
+	if shard == core.MetachainShardId {
+		return subNetworkMetachain
+	}
+
+	return strconv.FormatUint(uint64(shard), 10)
+}
+
+// GetNetworkIdentifiers returns the supported network identifiers: the ones of the sub-networks (the same as listed by /network/list).
+// The network identifier without a sub-network is not supported.
+func (router *subNetworksRouter) GetNetworkIdentifiers() []*types.NetworkIdentifier {
+	defaultProvider := router.getDefaultServices().provider
+	networkIdentifiers := make([]*types.NetworkIdentifier, 0, len(router.subNetworks))
+
+	for _, subNetwork := range router.subNetworks {
+		networkIdentifiers = append(networkIdentifiers, &types.NetworkIdentifier{
+			Blockchain: defaultProvider.GetBlockchainName(),
+			Network:    defaultProvider.GetChainID(),
+			SubNetworkIdentifier: &types.SubNetworkIdentifier{
+				Network: subNetwork,
+			},
+		})
+	}
+
+	return networkIdentifiers
+}
+
+// getDefaultServices returns the services of the first configured shard, used for computations that do not depend on the shard (e.g. the shard of an address)
+func (router *subNetworksRouter) getDefaultServices() *subNetworkServices {
+	return router.servicesBySubNetwork[router.subNetworks[0]]
+}
+
+func (router *subNetworksRouter) getServicesOfNetwork(networkIdentifier *types.NetworkIdentifier) (*subNetworkServices, *types.Error) {
+	if networkIdentifier == nil || networkIdentifier.SubNetworkIdentifier == nil {
+		return nil, router.errFactory.newErrWithOriginal(ErrUnsupportedSubNetwork, errors.New("sub-network must be specified"))
+	}
+
+	subNetwork := networkIdentifier.SubNetworkIdentifier.Network
+	services, ok := router.servicesBySubNetwork[subNetwork]
+	if !ok {
+		return nil, router.errFactory.newErrWithOriginal(ErrUnsupportedSubNetwork, fmt.Errorf("sub-network not observed: %s", subNetwork))
+	}
+
+	return services, nil
+}
+
+// getServicesOfAddress returns the services of the shard of the given address. The specified sub-network must match.
+func (router *subNetworksRouter) getServicesOfAddress(networkIdentifier *types.NetworkIdentifier, address string) (*subNetworkServices, *types.Error) {
+	if networkIdentifier == nil || networkIdentifier.SubNetworkIdentifier == nil {
+		return nil, router.errFactory.newErrWithOriginal(ErrUnsupportedSubNetwork, errors.New("sub-network must be specified"))
+	}
+
+	shard, err := router.getDefaultServices().provider.ComputeShardIdOfAddress(address)
+	if err != nil {
+		return nil, router.errFactory.newErrWithOriginal(ErrInvalidAccountAddress, err)
+	}
+
+	subNetwork := shardToSubNetwork(shard)
+	services, ok := router.servicesBySubNetwork[subNetwork]
+	if !ok {
+		return nil, router.errFactory.newErrWithOriginal(ErrUnsupportedSubNetwork, fmt.Errorf("shard of address not observed: %s", subNetwork))
+	}
+
+	if networkIdentifier.SubNetworkIdentifier.Network != subNetwork {
+		return nil, router.errFactory.newErrWithOriginal(ErrUnsupportedSubNetwork, fmt.Errorf("address belongs to sub-network %s", subNetwork))
+	}
+
+	return services, nil
+}
+
+// NetworkList implements the /network/list endpoint
+func (router *subNetworksRouter) NetworkList(
+	_ context.Context,
+	_ *types.MetadataRequest,
+) (*types.NetworkListResponse, *types.Error) {
+	return &types.NetworkListResponse{
+		NetworkIdentifiers: router.GetNetworkIdentifiers(),
+	}, nil
+}
+
+// NetworkStatus implements the /network/status endpoint.
+func (router *subNetworksRouter) NetworkStatus(
+	ctx context.Context,
+	request *types.NetworkRequest,
+) (*types.NetworkStatusResponse, *types.Error) {
+	services, err := router.getServicesOfNetwork(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return services.network.NetworkStatus(ctx, request)
+}
+
+// NetworkOptions implements the /network/options endpoint.
+func (router *subNetworksRouter) NetworkOptions(
+	ctx context.Context,
+	request *types.NetworkRequest,
+) (*types.NetworkOptionsResponse, *types.Error) {
+	services, err := router.getServicesOfNetwork(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return services.network.NetworkOptions(ctx, request)
+}
+
+// AccountBalance implements the /account/balance endpoint. The request is routed to the shard of the account.
+func (router *subNetworksRouter) AccountBalance(
+	ctx context.Context,
+	request *types.AccountBalanceRequest,
+) (*types.AccountBalanceResponse, *types.Error) {
+	services, err := router.getServicesOfAddress(request.NetworkIdentifier, request.AccountIdentifier.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	return services.account.AccountBalance(ctx, request)
+}
+
+// AccountCoins implements the /account/coins endpoint.
+func (router *subNetworksRouter) AccountCoins(
+	ctx context.Context,
+	request *types.AccountCoinsRequest,
+) (*types.AccountCoinsResponse, *types.Error) {
+	services, err := router.getServicesOfAddress(request.NetworkIdentifier, request.AccountIdentifier.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	return services.account.AccountCoins(ctx, request)
+}
+
+// Block implements the /block endpoint.
+func (router *subNetworksRouter) Block(
+	ctx context.Context,
+	request *types.BlockRequest,
+) (*types.BlockResponse, *types.Error) {
+	services, err := router.getServicesOfNetwork(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return services.block.Block(ctx, request)
+}
+
+// BlockTransaction implements the /block/transaction endpoint.
+func (router *subNetworksRouter) BlockTransaction(
+	ctx context.Context,
+	request *types.BlockTransactionRequest,
+) (*types.BlockTransactionResponse, *types.Error) {
+	services, err := router.getServicesOfNetwork(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return services.block.BlockTransaction(ctx, request)
+}
+
+// Mempool implements the /mempool endpoint.
+func (router *subNetworksRouter) Mempool(
+	ctx context.Context,
+	request *types.NetworkRequest,
+) (*types.MempoolResponse, *types.Error) {
+	services, err := router.getServicesOfNetwork(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return services.mempool.Mempool(ctx, request)
+}
+
+// MempoolTransaction implements the /mempool/transaction endpoint.
+func (router *subNetworksRouter) MempoolTransaction(
+	ctx context.Context,
+	request *types.MempoolTransactionRequest,
+) (*types.MempoolTransactionResponse, *types.Error) {
+	services, err := router.getServicesOfNetwork(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return services.mempool.MempoolTransaction(ctx, request)
+}
+
+// ConstructionDerive implements the /construction/derive endpoint.
+func (router *subNetworksRouter) ConstructionDerive(
+	ctx context.Context,
+	request *types.ConstructionDeriveRequest,
+) (*types.ConstructionDeriveResponse, *types.Error) {
+	services, err := router.getServicesOfNetwork(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return services.construction.ConstructionDerive(ctx, request)
+}
+
+// ConstructionPreprocess implements the /construction/preprocess endpoint.
+func (router *subNetworksRouter) ConstructionPreprocess(
+	ctx context.Context,
+	request *types.ConstructionPreprocessRequest,
+) (*types.ConstructionPreprocessResponse, *types.Error) {
+	services, err := router.getServicesOfNetwork(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return services.construction.ConstructionPreprocess(ctx, request)
+}
+
+// ConstructionMetadata implements the /construction/metadata endpoint. The request is routed to the shard of the sender,
+// since the metadata holds the nonce of the sender (and, possibly, simulation results).
+func (router *subNetworksRouter) ConstructionMetadata(
+	ctx context.Context,
+	request *types.ConstructionMetadataRequest,
+) (*types.ConstructionMetadataResponse, *types.Error) {
+	sender, ok := request.Options["sender"].(string)
+	if !ok {
+		return nil, router.errFactory.newErrWithOriginal(ErrInvalidInputParam, errors.New("invalid sender"))
+	}
+
+	services, err := router.getServicesOfAddress(request.NetworkIdentifier, sender)
+	if err != nil {
+		return nil, err
+	}
+
+	return services.construction.ConstructionMetadata(ctx, request)
+}
+
+// ConstructionPayloads implements the /construction/payloads endpoint.
+func (router *subNetworksRouter) ConstructionPayloads(
+	ctx context.Context,
+	request *types.ConstructionPayloadsRequest,
+) (*types.ConstructionPayloadsResponse, *types.Error) {
+	services, err := router.getServicesOfNetwork(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return services.construction.ConstructionPayloads(ctx, request)
+}
+
+// ConstructionParse implements the /construction/parse endpoint.
+func (router *subNetworksRouter) ConstructionParse(
+	ctx context.Context,
+	request *types.ConstructionParseRequest,
+) (*types.ConstructionParseResponse, *types.Error) {
+	services, err := router.getServicesOfNetwork(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return services.construction.ConstructionParse(ctx, request)
+}
+
+// ConstructionCombine implements the /construction/combine endpoint.
+func (router *subNetworksRouter) ConstructionCombine(
+	ctx context.Context,
+	request *types.ConstructionCombineRequest,
+) (*types.ConstructionCombineResponse, *types.Error) {
+	services, err := router.getServicesOfNetwork(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return services.construction.ConstructionCombine(ctx, request)
+}
+
+// ConstructionHash implements the /construction/hash endpoint.
+func (router *subNetworksRouter) ConstructionHash(
+	ctx context.Context,
+	request *types.ConstructionHashRequest,
+) (*types.TransactionIdentifierResponse, *types.Error) {
+	services, err := router.getServicesOfNetwork(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return services.construction.ConstructionHash(ctx, request)
+}
+
+// ConstructionSubmit implements the /construction/submit endpoint. The request is routed to the shard of the sender.
+func (router *subNetworksRouter) ConstructionSubmit(
+	ctx context.Context,
+	request *types.ConstructionSubmitRequest,
+) (*types.TransactionIdentifierResponse, *types.Error) {
+	tx, errGetTx := getTxFromRequest(request.SignedTransaction)
+	if errGetTx != nil {
+		return nil, router.errFactory.newErrOfTxFromRequest(errGetTx)
+	}
+
+	services, err := router.getServicesOfAddress(request.NetworkIdentifier, tx.Sender)
+	if err != nil {
+		return nil, err
+	}
+
+	return services.construction.ConstructionSubmit(ctx, request)
+}
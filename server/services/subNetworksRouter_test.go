@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ElrondNetwork/elrond-go-core/core"
+	"github.com/ElrondNetwork/elrond-proxy-go/data"
+	"github.com/ElrondNetwork/rosetta/testscommon"
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+func createNetworkProvidersForSubNetworksTests() []NetworkProvider {
+	shards := []uint32{0, 1, core.MetachainShardId}
+	networkProviders := make([]NetworkProvider, 0, len(shards))
+
+	for _, shard := range shards {
+		networkProvider := testscommon.NewNetworkProviderMock()
+		networkProvider.MockNetworkConfig.ChainID = "T"
+		networkProvider.MockObservedActualShard = shard
+		networkProvider.MockNodeStatus.PublicKeyBlockSign = shardToSubNetwork(shard) + "-observer"
+		// The balance tells which shard has been queried
+		networkProvider.MockAccountsByAddress[testscommon.TestAddressAlice] = &data.Account{
+			Address: testscommon.TestAddressAlice,
+			Balance: fmt.Sprintf("%d", shard),
+		}
+		networkProviders = append(networkProviders, networkProvider)
+	}
+
+	return networkProviders
+}
+
+func createSubNetworkIdentifier(subNetwork string) *types.NetworkIdentifier {
+	return &types.NetworkIdentifier{
+		Blockchain:           "Elrond",
+		Network:              "T",
+		SubNetworkIdentifier: &types.SubNetworkIdentifier{Network: subNetwork},
+	}
+}
+
+func TestNewSubNetworksRouter(t *testing.T) {
+	router, err := NewSubNetworksRouter(createNetworkProvidersForSubNetworksTests())
+	require.Nil(t, err)
+	require.Equal(t, []string{"0", "1", "metachain"}, router.subNetworks)
+
+	require.Equal(t, []*types.NetworkIdentifier{
+		createSubNetworkIdentifier("0"),
+		createSubNetworkIdentifier("1"),
+		createSubNetworkIdentifier("metachain"),
+	}, router.GetNetworkIdentifiers())
+
+	_, err = NewSubNetworksRouter(nil)
+	require.NotNil(t, err)
+
+	duplicated := testscommon.NewNetworkProviderMock()
+	_, err = NewSubNetworksRouter([]NetworkProvider{duplicated, duplicated})
+	require.NotNil(t, err)
+}
+
+func TestSubNetworksRouter_NetworkEndpoints(t *testing.T) {
+	router, err := NewSubNetworksRouter(createNetworkProvidersForSubNetworksTests())
+	require.Nil(t, err)
+
+	// The listed networks are the supported ones
+	networkList, errTyped := router.NetworkList(context.Background(), nil)
+	require.Nil(t, errTyped)
+	require.Equal(t, router.GetNetworkIdentifiers(), networkList.NetworkIdentifiers)
+
+	networkStatus, errTyped := router.NetworkStatus(context.Background(), &types.NetworkRequest{NetworkIdentifier: createSubNetworkIdentifier("1")})
+	require.Nil(t, errTyped)
+	require.Equal(t, "1-observer", networkStatus.Peers[0].PeerID)
+
+	_, errTyped = router.Block(context.Background(), &types.BlockRequest{NetworkIdentifier: createSubNetworkIdentifier("2")})
+	require.Equal(t, int32(ErrUnsupportedSubNetwork), errTyped.Code)
+
+	// All endpoints require a sub-network, even the ones that don't depend on the shard
+	_, errTyped = router.NetworkStatus(context.Background(), &types.NetworkRequest{NetworkIdentifier: &types.NetworkIdentifier{Blockchain: "Elrond", Network: "T"}})
+	require.Equal(t, int32(ErrUnsupportedSubNetwork), errTyped.Code)
+
+	_, errTyped = router.NetworkOptions(context.Background(), &types.NetworkRequest{NetworkIdentifier: &types.NetworkIdentifier{Blockchain: "Elrond", Network: "T"}})
+	require.Equal(t, int32(ErrUnsupportedSubNetwork), errTyped.Code)
+
+	_, errTyped = router.NetworkOptions(context.Background(), &types.NetworkRequest{NetworkIdentifier: createSubNetworkIdentifier("metachain")})
+	require.Nil(t, errTyped)
+}
+
+func TestSubNetworksRouter_AccountBalance(t *testing.T) {
+	// Alice is in shard 1, Carol is in shard 2 (not observed)
+	router, err := NewSubNetworksRouter(createNetworkProvidersForSubNetworksTests())
+	require.Nil(t, err)
+
+	t.Run("with matching sub-network", func(t *testing.T) {
+		response, errTyped := router.AccountBalance(context.Background(), &types.AccountBalanceRequest{
+			NetworkIdentifier: createSubNetworkIdentifier("1"),
+			AccountIdentifier: &types.AccountIdentifier{Address: testscommon.TestAddressAlice},
+		})
+		require.Nil(t, errTyped)
+		require.Equal(t, "1", response.Balances[0].Value)
+	})
+
+	t.Run("without sub-network", func(t *testing.T) {
+		_, errTyped := router.AccountBalance(context.Background(), &types.AccountBalanceRequest{
+			NetworkIdentifier: &types.NetworkIdentifier{Blockchain: "Elrond", Network: "T"},
+			AccountIdentifier: &types.AccountIdentifier{Address: testscommon.TestAddressAlice},
+		})
+		require.Equal(t, int32(ErrUnsupportedSubNetwork), errTyped.Code)
+	})
+
+	t.Run("with mismatching sub-network", func(t *testing.T) {
+		_, errTyped := router.AccountBalance(context.Background(), &types.AccountBalanceRequest{
+			NetworkIdentifier: createSubNetworkIdentifier("0"),
+			AccountIdentifier: &types.AccountIdentifier{Address: testscommon.TestAddressAlice},
+		})
+		require.Equal(t, int32(ErrUnsupportedSubNetwork), errTyped.Code)
+	})
+
+	t.Run("shard of address not observed", func(t *testing.T) {
+		_, errTyped := router.AccountBalance(context.Background(), &types.AccountBalanceRequest{
+			NetworkIdentifier: createSubNetworkIdentifier("1"),
+			AccountIdentifier: &types.AccountIdentifier{Address: testscommon.TestAddressCarol},
+		})
+		require.Equal(t, int32(ErrUnsupportedSubNetwork), errTyped.Code)
+	})
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+
+	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
+	"github.com/ElrondNetwork/elrond-proxy-go/data"
+)
+
+// innerTransaction is the (user) transaction held by a relayed transaction
+type innerTransaction struct {
+	sender   string
+	receiver string
+	value    string
+	data     []byte
+}
+
+// transactionDataParser decodes the data field of transactions that aren't yet executed (e.g. transactions in the pool),
+// for which the events (logs) aren't available.
+type transactionDataParser struct {
+	provider NetworkProvider
+}
+
+func newTransactionDataParser(provider NetworkProvider) *transactionDataParser {
+	return &transactionDataParser{
+		provider: provider,
+	}
+}
+
+// parseRelayedTransaction decodes the inner transaction of a relayed transaction ("relayedTx@" or "relayedTxV2@")
+func (parser *transactionDataParser) parseRelayedTransaction(tx *data.FullTransaction) (*innerTransaction, bool) {
+	function, args := splitDataField(tx.Data)
+
+	switch function {
+	case builtInFunctionRelayedTx:
+		return parser.parseRelayedTxV1(args)
+	case builtInFunctionRelayedTxV2:
+		return parser.parseRelayedTxV2(tx, args)
+	default:
+		return nil, false
+	}
+}
+
+// parseRelayedTxV1 decodes "relayedTx@<inner transaction, JSON-serialized>"
+func (parser *transactionDataParser) parseRelayedTxV1(args [][]byte) (*innerTransaction, bool) {
+	if len(args) != 1 {
+		return nil, false
+	}
+
+	inner := &transaction.Transaction{}
+	err := json.Unmarshal(args[0], inner)
+	if err != nil {
+		return nil, false
+	}
+
+	value := "0"
+	if inner.Value != nil {
+		value = inner.Value.String()
+	}
+
+	return &innerTransaction{
+		sender:   parser.provider.ConvertPubKeyToAddress(inner.SndAddr),
+		receiver: parser.provider.ConvertPubKeyToAddress(inner.RcvAddr),
+		value:    value,
+		data:     inner.Data,
+	}, true
+}
+
+// parseRelayedTxV2 decodes "relayedTxV2@<receiver>@<nonce>@<data>@<signature>". The sender of the inner transaction is the receiver
+// of the relayed one; the inner transaction cannot move native value.
+func (parser *transactionDataParser) parseRelayedTxV2(tx *data.FullTransaction, args [][]byte) (*innerTransaction, bool) {
+	if len(args) != 4 {
+		return nil, false
+	}
+
+	return &innerTransaction{
+		sender:   tx.Receiver,
+		receiver: parser.provider.ConvertPubKeyToAddress(args[0]),
+		value:    "0",
+		data:     args[2],
+	}, true
+}
+
+// parseTokenTransfers decodes the token transfers of "ESDTTransfer", "ESDTNFTTransfer" and "MultiESDTNFTTransfer".
+// The transfers are returned as events, with the same shape as the ones emitted by the protocol (once the transaction is executed).
+func (parser *transactionDataParser) parseTokenTransfers(sender string, receiver string, dataField []byte) []*eventESDT {
+	function, args := splitDataField(dataField)
+
+	switch function {
+	case builtInFunctionESDTTransfer:
+		// ESDTTransfer@<token>@<value>[@<function>@<args>...]
+		if len(args) < 2 {
+			return nil
+		}
+
+		return []*eventESDT{newEventESDTGivenArgs(sender, receiver, args[0], nil, args[1])}
+	case builtInFunctionESDTNFTTransfer:
+		// ESDTNFTTransfer@<token>@<nonce>@<quantity>@<receiver>[@<function>@<args>...] (sent to self)
+		if len(args) < 4 {
+			return nil
+		}
+
+		actualReceiver := parser.provider.ConvertPubKeyToAddress(args[3])
+		return []*eventESDT{newEventESDTGivenArgs(sender, actualReceiver, args[0], args[1], args[2])}
+	case builtInFunctionMultiESDTNFTTransfer:
+		// MultiESDTNFTTransfer@<receiver>@<numTransfers>(@<token>@<nonce>@<quantity>)...[@<function>@<args>...] (sent to self)
+		if len(args) < 2 {
+			return nil
+		}
+
+		actualReceiver := parser.provider.ConvertPubKeyToAddress(args[0])
+		numTransfers := big.NewInt(0).SetBytes(args[1]).Uint64()
+		if uint64(len(args)-2) < numTransfers*3 {
+			return nil
+		}
+
+		events := make([]*eventESDT, 0, numTransfers)
+		for i := uint64(0); i < numTransfers; i++ {
+			offset := 2 + i*3
+			events = append(events, newEventESDTGivenArgs(sender, actualReceiver, args[offset], args[offset+1], args[offset+2]))
+		}
+
+		return events
+	default:
+		return nil
+	}
+}
+
+func newEventESDTGivenArgs(sender string, receiver string, identifier []byte, nonce []byte, value []byte) *eventESDT {
+	return &eventESDT{
+		sender:     sender,
+		receiver:   receiver,
+		identifier: string(identifier),
+		// Nonces are normalized (e.g. "00" stands for fungible tokens, as well)
+		nonceBytes: big.NewInt(0).SetBytes(nonce).Bytes(),
+		value:      big.NewInt(0).SetBytes(value).String(),
+	}
+}
+
+// splitDataField splits a data field of shape "function@arg1@arg2..." and hex-decodes the arguments.
+// If any of the arguments cannot be decoded, no arguments are returned.
+func splitDataField(dataField []byte) (string, [][]byte) {
+	parts := bytes.Split(dataField, []byte("@"))
+	function := string(parts[0])
+	args := make([][]byte, 0, len(parts)-1)
+
+	for _, part := range parts[1:] {
+		arg, err := hex.DecodeString(string(part))
+		if err != nil {
+			return function, nil
+		}
+
+		args = append(args, arg)
+	}
+
+	return function, args
+}
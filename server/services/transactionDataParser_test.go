@@ -0,0 +1,108 @@
+package services
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
+	"github.com/ElrondNetwork/elrond-proxy-go/data"
+	"github.com/ElrondNetwork/rosetta/testscommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionDataParser_ParseRelayedTransaction(t *testing.T) {
+	networkProvider := testscommon.NewNetworkProviderMock()
+	parser := newTransactionDataParser(networkProvider)
+
+	t.Run("relayed v1", func(t *testing.T) {
+		innerTxJson, err := json.Marshal(&transaction.Transaction{
+			Nonce:   7,
+			Value:   big.NewInt(1234),
+			RcvAddr: testscommon.TestPubKeyBob,
+			SndAddr: testscommon.TestPubKeyAlice,
+			Data:    []byte("hello"),
+		})
+		require.Nil(t, err)
+
+		tx := &data.FullTransaction{
+			Sender:   testscommon.TestAddressOfContract,
+			Receiver: testscommon.TestAddressAlice,
+			Data:     []byte("relayedTx@" + hex.EncodeToString(innerTxJson)),
+		}
+
+		inner, ok := parser.parseRelayedTransaction(tx)
+		require.True(t, ok)
+		require.Equal(t, &innerTransaction{
+			sender:   testscommon.TestAddressAlice,
+			receiver: testscommon.TestAddressBob,
+			value:    "1234",
+			data:     []byte("hello"),
+		}, inner)
+	})
+
+	t.Run("relayed v2", func(t *testing.T) {
+		tx := &data.FullTransaction{
+			Sender:   testscommon.TestAddressOfContract,
+			Receiver: testscommon.TestAddressAlice,
+			Data:     []byte("relayedTxV2@" + testscommon.TestPubKeyHexBob + "@07@" + hex.EncodeToString([]byte("hello")) + "@abcd"),
+		}
+
+		inner, ok := parser.parseRelayedTransaction(tx)
+		require.True(t, ok)
+		require.Equal(t, &innerTransaction{
+			sender:   testscommon.TestAddressAlice,
+			receiver: testscommon.TestAddressBob,
+			value:    "0",
+			data:     []byte("hello"),
+		}, inner)
+	})
+
+	t.Run("not relayed, or malformed", func(t *testing.T) {
+		_, ok := parser.parseRelayedTransaction(&data.FullTransaction{Data: []byte("hello")})
+		require.False(t, ok)
+
+		_, ok = parser.parseRelayedTransaction(&data.FullTransaction{Data: []byte("relayedTx@abcd")})
+		require.False(t, ok)
+
+		_, ok = parser.parseRelayedTransaction(&data.FullTransaction{Data: []byte("relayedTxV2@zz@07@aa@bb")})
+		require.False(t, ok)
+	})
+}
+
+func TestTransactionDataParser_ParseTokenTransfers(t *testing.T) {
+	networkProvider := testscommon.NewNetworkProviderMock()
+	parser := newTransactionDataParser(networkProvider)
+
+	t.Run("ESDTTransfer", func(t *testing.T) {
+		events := parser.parseTokenTransfers(testscommon.TestAddressAlice, testscommon.TestAddressBob, []byte("ESDTTransfer@524f53455454412d336132656466@64"))
+		require.Len(t, events, 1)
+		require.Equal(t, "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th -> erd1spyavw0956vq68xj8y4tenjpq2wd5a9p2c6j8gsz7ztyrnpxrruqzu66jx (100 ROSETTA-3a2edf)", events[0].String())
+	})
+
+	t.Run("ESDTNFTTransfer", func(t *testing.T) {
+		dataField := "ESDTNFTTransfer@4558414d504c452d343533626563@0a@01@" + testscommon.TestPubKeyHexBob
+		events := parser.parseTokenTransfers(testscommon.TestAddressAlice, testscommon.TestAddressAlice, []byte(dataField))
+		require.Len(t, events, 1)
+		require.Equal(t, testscommon.TestAddressBob, events[0].receiver)
+		require.Equal(t, "EXAMPLE-453bec-0a", events[0].getExtendedIdentifier())
+		require.Equal(t, "1", events[0].value)
+	})
+
+	t.Run("MultiESDTNFTTransfer", func(t *testing.T) {
+		dataField := "MultiESDTNFTTransfer@" + testscommon.TestPubKeyHexBob + "@02@524f53455454412d336132656466@00@64@4558414d504c452d343533626563@0a@01@6465706f736974"
+		events := parser.parseTokenTransfers(testscommon.TestAddressAlice, testscommon.TestAddressAlice, []byte(dataField))
+		require.Len(t, events, 2)
+		require.Equal(t, "ROSETTA-3a2edf", events[0].getExtendedIdentifier())
+		require.Equal(t, "100", events[0].value)
+		require.Equal(t, "EXAMPLE-453bec-0a", events[1].getExtendedIdentifier())
+		require.Equal(t, testscommon.TestAddressBob, events[1].receiver)
+	})
+
+	t.Run("not a token transfer, or malformed", func(t *testing.T) {
+		require.Len(t, parser.parseTokenTransfers(testscommon.TestAddressAlice, testscommon.TestAddressBob, []byte("hello")), 0)
+		require.Len(t, parser.parseTokenTransfers(testscommon.TestAddressAlice, testscommon.TestAddressBob, []byte("ESDTTransfer@zz@64")), 0)
+		require.Len(t, parser.parseTokenTransfers(testscommon.TestAddressAlice, testscommon.TestAddressBob, []byte("MultiESDTNFTTransfer@"+testscommon.TestPubKeyHexBob+"@05@aa@00@01")), 0)
+	})
+}
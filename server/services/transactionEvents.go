@@ -1,6 +1,7 @@
 package services
 
 import (
+	"encoding/hex"
 	"fmt"
 )
 
@@ -13,3 +14,33 @@ type eventTransferValueOnly struct {
 func (event *eventTransferValueOnly) String() string {
 	return fmt.Sprintf("%s -> %s (%s)", event.sender, event.receiver, event.value)
 }
+
+func (event *eventTransferValueOnly) toValueMovement(originalTxHash string) valueMovement {
+	return valueMovement{
+		originalTxHash: originalTxHash,
+		sender:         event.sender,
+		receiver:       event.receiver,
+		value:          event.value,
+	}
+}
+
+type eventESDT struct {
+	sender     string
+	receiver   string
+	identifier string
+	nonceBytes []byte
+	value      string
+}
+
+// getExtendedIdentifier returns the token identifier for fungible tokens, and the token identifier suffixed by the nonce (hex-encoded) for the others
+func (event *eventESDT) getExtendedIdentifier() string {
+	if len(event.nonceBytes) > 0 {
+		return fmt.Sprintf("%s-%s", event.identifier, hex.EncodeToString(event.nonceBytes))
+	}
+
+	return event.identifier
+}
+
+func (event *eventESDT) String() string {
+	return fmt.Sprintf("%s -> %s (%s %s)", event.sender, event.receiver, event.value, event.getExtendedIdentifier())
+}
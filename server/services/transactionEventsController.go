@@ -33,30 +33,124 @@ func (controller *transactionEventsController) findEventByIdentifier(tx *data.Fu
 	return nil, errEventNotFound
 }
 
-func (controller *transactionEventsController) extractEventTransferValueOnly(tx *data.FullTransaction) (*eventTransferValueOnly, error) {
-	event, err := controller.findEventByIdentifier(tx, transactionEventTransferValueOnly)
+// extractEventSCDeploy returns the address of the deployed contract (the event is emitted by the contract itself)
+func (controller *transactionEventsController) extractEventSCDeploy(tx *data.FullTransaction) (string, error) {
+	event, err := controller.findEventByIdentifier(tx, transactionEventSCDeploy)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	numTopics := len(event.Topics)
-	if numTopics != 3 {
-		return nil, fmt.Errorf("%w: bad number of topics for 'transferValueOnly' = %d", errCannotRecognizeEvent, numTopics)
+	return event.Address, nil
+}
+
+func (controller *transactionEventsController) extractEventsTransferValueOnly(tx *data.FullTransaction) ([]*eventTransferValueOnly, error) {
+	if !controller.hasEvents(tx) {
+		return make([]*eventTransferValueOnly, 0), nil
+	}
+
+	events := make([]*eventTransferValueOnly, 0)
+
+	for _, event := range tx.Logs.Events {
+		if event.Identifier != transactionEventTransferValueOnly {
+			continue
+		}
+
+		numTopics := len(event.Topics)
+		if numTopics != 3 {
+			return nil, fmt.Errorf("%w: bad number of topics for 'transferValueOnly' = %d", errCannotRecognizeEvent, numTopics)
+		}
+
+		senderPubkey := event.Topics[0]
+		receiverPubkey := event.Topics[1]
+		valueBytes := event.Topics[2]
+
+		sender := controller.provider.ConvertPubKeyToAddress(senderPubkey)
+		receiver := controller.provider.ConvertPubKeyToAddress(receiverPubkey)
+		value := big.NewInt(0).SetBytes(valueBytes)
+
+		events = append(events, &eventTransferValueOnly{
+			sender:   sender,
+			receiver: receiver,
+			value:    value.String(),
+		})
+	}
+
+	return events, nil
+}
+
+func (controller *transactionEventsController) extractEventsESDTTransfers(tx *data.FullTransaction) ([]*eventESDT, error) {
+	if !controller.hasEvents(tx) {
+		return make([]*eventESDT, 0), nil
+	}
+
+	events := make([]*eventESDT, 0)
+
+	for _, event := range tx.Logs.Events {
+		if !isEventOfESDTTransfer(event) {
+			continue
+		}
+
+		// Events of "MultiESDTNFTTransfer" are emitted once per token, thus have the same shape as the other ones.
+		numTopics := len(event.Topics)
+		if numTopics != 4 {
+			return nil, fmt.Errorf("%w: bad number of topics for '%s' = %d", errCannotRecognizeEvent, event.Identifier, numTopics)
+		}
+
+		identifier := event.Topics[0]
+		nonceBytes := event.Topics[1]
+		valueBytes := event.Topics[2]
+		receiverPubkey := event.Topics[3]
+
+		receiver := controller.provider.ConvertPubKeyToAddress(receiverPubkey)
+		value := big.NewInt(0).SetBytes(valueBytes)
+
+		events = append(events, &eventESDT{
+			sender:     event.Address,
+			receiver:   receiver,
+			identifier: string(identifier),
+			nonceBytes: nonceBytes,
+			value:      value.String(),
+		})
 	}
 
-	senderPubkey := event.Topics[0]
-	receiverPubkey := event.Topics[1]
-	valueBytes := event.Topics[2]
+	return events, nil
+}
+
+func (controller *transactionEventsController) hasEventsOfESDTTransfer(tx *data.FullTransaction) bool {
+	if !controller.hasEvents(tx) {
+		return false
+	}
+
+	for _, event := range tx.Logs.Events {
+		if isEventOfESDTTransfer(event) {
+			return true
+		}
+	}
+
+	return false
+}
 
-	sender := controller.provider.ConvertPubKeyToAddress(senderPubkey)
-	receiver := controller.provider.ConvertPubKeyToAddress(receiverPubkey)
-	value := big.NewInt(0).SetBytes(valueBytes)
+func (controller *transactionEventsController) hasEventsOfTransferValueOnly(tx *data.FullTransaction) bool {
+	if !controller.hasEvents(tx) {
+		return false
+	}
 
-	return &eventTransferValueOnly{
-		sender:   sender,
-		receiver: receiver,
-		value:    value.String(),
-	}, nil
+	for _, event := range tx.Logs.Events {
+		if event.Identifier == transactionEventTransferValueOnly {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isEventOfESDTTransfer(event *transaction.Events) bool {
+	switch event.Identifier {
+	case transactionEventESDTTransfer, transactionEventESDTNFTTransfer, transactionEventMultiESDTNFTTransfer:
+		return true
+	default:
+		return false
+	}
 }
 
 func (controller *transactionEventsController) hasSignalErrorOfSendingValueToNonPayableContract(tx *data.FullTransaction) bool {
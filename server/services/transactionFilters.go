@@ -1,11 +1,27 @@
 package services
 
 import (
+	dataBlock "github.com/ElrondNetwork/elrond-go-core/data/block"
 	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
 	"github.com/ElrondNetwork/elrond-proxy-go/data"
-	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
+// filterOutPeerMiniblocks removes the miniblocks holding validator (peer) changes, which can be found in metablocks.
+// They do not hold any transfer of value, thus they are not relevant for Rosetta.
+func filterOutPeerMiniblocks(miniblocks []*data.MiniBlock) []*data.MiniBlock {
+	filteredMiniblocks := make([]*data.MiniBlock, 0, len(miniblocks))
+
+	for _, miniblock := range miniblocks {
+		if miniblock.Type == dataBlock.PeerBlock.String() {
+			continue
+		}
+
+		filteredMiniblocks = append(filteredMiniblocks, miniblock)
+	}
+
+	return filteredMiniblocks
+}
+
 func filterOutIntrashardContractResultsWhoseOriginalTransactionIsInInvalidMiniblock(txs []*data.FullTransaction) []*data.FullTransaction {
 	filteredTxs := make([]*data.FullTransaction, 0, len(txs))
 	invalidTxs := make(map[string]struct{})
@@ -57,15 +73,21 @@ func filterOutIntrashardRelayedTransactionAlreadyHeldInInvalidMiniblock(txs []*d
 	return filteredTxs
 }
 
-func filterOutContractResultsWithNoValue(txs []*data.FullTransaction) []*data.FullTransaction {
+// filterOutContractResultsWithNoValueNorTokenTransfers discards contract results that do not move native value,
+// unless they hold events of ESDT transfers (e.g. the results of cross-shard token transfers, on the destination shard)
+// or "transferValueOnly" events (e.g. value sent by a contract, during the execution of a contract result with no value).
+func filterOutContractResultsWithNoValueNorTokenTransfers(
+	txs []*data.FullTransaction,
+	eventsController *transactionEventsController,
+) []*data.FullTransaction {
 	filteredTxs := make([]*data.FullTransaction, 0, len(txs))
 
 	for _, tx := range txs {
 		isContractResult := tx.Type == string(transaction.TxTypeUnsigned)
-		hasValue := tx.Value != "0" && tx.Value != ""
-		hasNegativeValue := hasValue && tx.Value[0] == '-'
+		hasTokenTransfers := eventsController.hasEventsOfESDTTransfer(tx)
+		hasValueTransfers := eventsController.hasEventsOfTransferValueOnly(tx)
 
-		if isContractResult && (!hasValue || hasNegativeValue) {
+		if isContractResult && isZeroOrNegativeValue(tx.Value) && !hasTokenTransfers && !hasValueTransfers {
 			continue
 		}
 
@@ -75,14 +97,28 @@ func filterOutContractResultsWithNoValue(txs []*data.FullTransaction) []*data.Fu
 	return filteredTxs
 }
 
-func filterOutRosettaTransactionsWithNoOperations(rosettaTxs []*types.Transaction) []*types.Transaction {
-	filtered := make([]*types.Transaction, 0, len(rosettaTxs))
+func isZeroOrNegativeValue(value string) bool {
+	return value == "" || value == "0" || value[0] == '-'
+}
+
+// filterOutContractResultsOfRelayedInnerTransfers discards the contract results that carry the value movement of inner transactions
+// (since the relayed transactions, within the same block, report the inner transfer themselves).
+func filterOutContractResultsOfRelayedInnerTransfers(txs []*data.FullTransaction, contractResultsByRelayedTxHash map[string]*data.FullTransaction) []*data.FullTransaction {
+	filteredTxs := make([]*data.FullTransaction, 0, len(txs))
+	contractResultsToDiscard := make(map[string]struct{}, len(contractResultsByRelayedTxHash))
 
-	for _, rosettaTx := range rosettaTxs {
-		if len(rosettaTx.Operations) > 0 {
-			filtered = append(filtered, rosettaTx)
+	for _, contractResult := range contractResultsByRelayedTxHash {
+		contractResultsToDiscard[contractResult.Hash] = struct{}{}
+	}
+
+	for _, tx := range txs {
+		_, shouldDiscard := contractResultsToDiscard[tx.Hash]
+		if shouldDiscard {
+			continue
 		}
+
+		filteredTxs = append(filteredTxs, tx)
 	}
 
-	return filtered
+	return filteredTxs
 }
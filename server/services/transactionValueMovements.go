@@ -0,0 +1,83 @@
+package services
+
+import (
+	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
+	"github.com/ElrondNetwork/elrond-proxy-go/data"
+)
+
+// valueMovement is a transfer of native value, as reported by a transaction, by a contract result or by a "transferValueOnly" event.
+// It is scoped by the hash of the original transaction (the one that started the execution).
+type valueMovement struct {
+	originalTxHash string
+	sender         string
+	receiver       string
+	value          string
+}
+
+// valueMovementsIndex counts the value movements already reported (as operations) by the transactions and contract results of a block.
+// It is used to reconcile "transferValueOnly" events, which duplicate the information of contract results in most contexts
+// (e.g. cross-shard calls, or async calls whose value is carried by a contract result), but not in all of them
+// (e.g. intra-shard async calls that produce no contract result with value).
+// A movement only covers the events of the same original transaction - an unrelated contract result (with the same sender,
+// receiver and value) does not.
+type valueMovementsIndex struct {
+	counts map[valueMovement]int
+}
+
+func newValueMovementsIndex(txsInBlock []*data.FullTransaction, featuresDetector *transactionsFeaturesDetector) *valueMovementsIndex {
+	index := &valueMovementsIndex{
+		counts: make(map[valueMovement]int),
+	}
+
+	for _, tx := range txsInBlock {
+		isNormalTx := tx.Type == string(transaction.TxTypeNormal)
+		isContractResult := tx.Type == string(transaction.TxTypeUnsigned)
+
+		if !isNormalTx && !isContractResult {
+			continue
+		}
+		if tx.IsRefund || isZeroOrNegativeValue(tx.Value) {
+			continue
+		}
+
+		receiver := tx.Receiver
+		if deployedContract, ok := featuresDetector.getAddressOfDeployedContract(tx); ok {
+			receiver = deployedContract
+		}
+
+		index.add(valueMovement{
+			originalTxHash: getOriginalTransactionHash(tx),
+			sender:         tx.Sender,
+			receiver:       receiver,
+			value:          tx.Value,
+		})
+	}
+
+	return index
+}
+
+func (index *valueMovementsIndex) add(movement valueMovement) {
+	index.counts[movement]++
+}
+
+// consume tells whether the value movement is already covered by a transaction or by a contract result.
+// Each transaction (or contract result) covers at most one value movement.
+func (index *valueMovementsIndex) consume(movement valueMovement) bool {
+	count := index.counts[movement]
+	if count == 0 {
+		return false
+	}
+
+	index.counts[movement] = count - 1
+	return true
+}
+
+// getOriginalTransactionHash returns the hash of the transaction that started the execution: for contract results,
+// the original transaction; for transactions, the transaction itself.
+func getOriginalTransactionHash(tx *data.FullTransaction) string {
+	if len(tx.OriginalTransactionHash) > 0 {
+		return tx.OriginalTransactionHash
+	}
+
+	return tx.Hash
+}
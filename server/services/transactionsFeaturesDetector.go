@@ -8,15 +8,40 @@ import (
 )
 
 type transactionsFeaturesDetector struct {
+	provider         NetworkProvider
 	eventsController *transactionEventsController
+	dataParser       *transactionDataParser
 }
 
 func newTransactionsFeaturesDetector(provider NetworkProvider) *transactionsFeaturesDetector {
 	return &transactionsFeaturesDetector{
+		provider:         provider,
 		eventsController: newTransactionEventsController(provider),
+		dataParser:       newTransactionDataParser(provider),
 	}
 }
 
+// getAddressOfDeployedContract returns the address of the contract deployed by a transaction (if any). Deployments are sent to the
+// "zero" address, while the value is actually transferred to the newly created contract (as signaled by the "SCDeploy" event).
+func (extractor *transactionsFeaturesDetector) getAddressOfDeployedContract(tx *data.FullTransaction) (string, bool) {
+	receiverPubkey, err := extractor.provider.ConvertAddressToPubKey(tx.Receiver)
+	if err != nil {
+		return "", false
+	}
+
+	isDeployment := bytes.Equal(receiverPubkey, make([]byte, len(receiverPubkey)))
+	if !isDeployment {
+		return "", false
+	}
+
+	contractAddress, err := extractor.eventsController.extractEventSCDeploy(tx)
+	if err != nil {
+		return "", false
+	}
+
+	return contractAddress, true
+}
+
 // Example SCRs can be found here: https://api.elrond.com/transactions?function=ClaimDeveloperRewards
 func (extractor *transactionsFeaturesDetector) doesContractResultHoldRewardsOfClaimDeveloperRewards(
 	contractResult *data.FullTransaction,
@@ -53,3 +78,47 @@ func (extractor *transactionsFeaturesDetector) isInvalidTransactionOfSendingValu
 
 	return extractor.eventsController.hasSignalErrorOfSendingValueToNonPayableContract(tx)
 }
+
+// findContractResultsOfRelayedInnerTransfers maps relayed transactions (whose inner transaction moves native value) to the contract results,
+// within the same block, that carry the inner value movement (i.e. the result of executing the inner transaction, on the shard of the inner sender).
+// A relayed transaction without such a contract result in the block (e.g. on the shard of the relayer, for cross-shard relayed transactions)
+// isn't included in the returned map.
+func (extractor *transactionsFeaturesDetector) findContractResultsOfRelayedInnerTransfers(txsInBlock []*data.FullTransaction) map[string]*data.FullTransaction {
+	innerTxsByRelayedTxHash := make(map[string]*innerTransaction)
+
+	for _, tx := range txsInBlock {
+		if tx.Type != string(transaction.TxTypeNormal) {
+			continue
+		}
+
+		inner, isRelayed := extractor.dataParser.parseRelayedTransaction(tx)
+		if !isRelayed || isZeroOrNegativeValue(inner.value) {
+			continue
+		}
+
+		innerTxsByRelayedTxHash[tx.Hash] = inner
+	}
+
+	contractResultsByRelayedTxHash := make(map[string]*data.FullTransaction)
+
+	for _, tx := range txsInBlock {
+		if tx.Type != string(transaction.TxTypeUnsigned) {
+			continue
+		}
+
+		inner, ok := innerTxsByRelayedTxHash[tx.OriginalTransactionHash]
+		if !ok {
+			continue
+		}
+		if _, alreadyFound := contractResultsByRelayedTxHash[tx.OriginalTransactionHash]; alreadyFound {
+			continue
+		}
+
+		isInnerTransfer := tx.Sender == inner.sender && tx.Receiver == inner.receiver && tx.Value == inner.value
+		if isInnerTransfer {
+			contractResultsByRelayedTxHash[tx.OriginalTransactionHash] = tx
+		}
+	}
+
+	return contractResultsByRelayedTxHash
+}
@@ -1,11 +1,11 @@
 package services
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
 	"github.com/ElrondNetwork/elrond-proxy-go/data"
+	"github.com/ElrondNetwork/rosetta/server/resources"
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
@@ -18,6 +18,7 @@ type transactionsTransformer struct {
 	extension        *networkProviderExtension
 	featuresDetector *transactionsFeaturesDetector
 	eventsController *transactionEventsController
+	dataParser       *transactionDataParser
 }
 
 func newTransactionsTransformer(provider NetworkProvider) *transactionsTransformer {
@@ -26,63 +27,215 @@ func newTransactionsTransformer(provider NetworkProvider) *transactionsTransform
 		extension:        newNetworkProviderExtension(provider),
 		featuresDetector: newTransactionsFeaturesDetector(provider),
 		eventsController: newTransactionEventsController(provider),
+		dataParser:       newTransactionDataParser(provider),
 	}
 }
 
 func (transformer *transactionsTransformer) transformTxsFromBlock(block *data.Block) ([]*types.Transaction, error) {
+	rosettaTxs := make([]*types.Transaction, 0)
+
+	err := transformer.doTransformTxsFromBlock(block, func(rosettaTx *types.Transaction) {
+		rosettaTxs = append(rosettaTxs, rosettaTx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rosettaTxs, nil
+}
+
+// txsOfBlock holds the transactions and the refund receipts of a block, filtered but not transformed,
+// along with the context needed in order to transform them.
+type txsOfBlock struct {
+	blockNonce            uint64
+	txs                   []*data.FullTransaction
+	refundReceipts        []*transaction.ApiReceipt
+	coveredValueMovements *valueMovementsIndex
+	relayedInnerTransfers map[string]*data.FullTransaction
+}
+
+func (transformer *transactionsTransformer) prepareTxsOfBlock(block *data.Block) *txsOfBlock {
 	txs := make([]*data.FullTransaction, 0)
-	receipts := make([]*transaction.ApiReceipt, 0)
+	refundReceipts := make([]*transaction.ApiReceipt, 0)
 
-	for _, miniblock := range block.MiniBlocks {
+	for _, miniblock := range filterOutPeerMiniblocks(block.MiniBlocks) {
 		for _, tx := range miniblock.Transactions {
 			txs = append(txs, tx)
 		}
 		for _, receipt := range miniblock.Receipts {
-			receipts = append(receipts, receipt)
+			if receipt.Data == refundGasMessage {
+				refundReceipts = append(refundReceipts, receipt)
+			}
 		}
 	}
 
 	txs = filterOutIntrashardContractResultsWhoseOriginalTransactionIsInInvalidMiniblock(txs)
 	txs = filterOutIntrashardRelayedTransactionAlreadyHeldInInvalidMiniblock(txs)
-	txs = filterOutContractResultsWithNoValue(txs)
+	txs = filterOutContractResultsWithNoValueNorTokenTransfers(txs, transformer.eventsController)
 
-	rosettaTxs := make([]*types.Transaction, 0)
-	for _, tx := range txs {
-		rosettaTx, err := transformer.txToRosettaTx(tx, txs)
+	coveredValueMovements := newValueMovementsIndex(txs, transformer.featuresDetector)
+
+	relayedInnerTransfers := transformer.featuresDetector.findContractResultsOfRelayedInnerTransfers(txs)
+	txs = filterOutContractResultsOfRelayedInnerTransfers(txs, relayedInnerTransfers)
+
+	return &txsOfBlock{
+		blockNonce:            block.Nonce,
+		txs:                   txs,
+		refundReceipts:        refundReceipts,
+		coveredValueMovements: coveredValueMovements,
+		relayedInnerTransfers: relayedInnerTransfers,
+	}
+}
+
+// doTransformTxsFromBlock transforms the transactions of a block (one by one), and passes those having operations of interest to the handler.
+func (transformer *transactionsTransformer) doTransformTxsFromBlock(
+	block *data.Block,
+	handleRosettaTx func(rosettaTx *types.Transaction),
+) error {
+	prepared := transformer.prepareTxsOfBlock(block)
+
+	for _, tx := range prepared.txs {
+		rosettaTx, err := transformer.transformTxOfBlock(tx, prepared)
+		if err != nil {
+			return err
+		}
+
+		err = transformer.finalizeRosettaTx(rosettaTx, handleRosettaTx)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, receipt := range prepared.refundReceipts {
+		rosettaTx, err := transformer.refundReceiptToRosettaTx(receipt)
+		if err != nil {
+			return err
+		}
+
+		err = transformer.finalizeRosettaTx(rosettaTx, handleRosettaTx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (transformer *transactionsTransformer) transformTxOfBlock(tx *data.FullTransaction, prepared *txsOfBlock) (*types.Transaction, error) {
+	rosettaTx, err := transformer.txToRosettaTx(tx, prepared.txs, prepared.blockNonce, prepared.coveredValueMovements)
+	if err != nil {
+		return nil, err
+	}
+
+	if innerTransfer, ok := prepared.relayedInnerTransfers[tx.Hash]; ok {
+		transformer.addOperationsGivenRelayedInnerTransfer(innerTransfer, rosettaTx)
+	}
+
+	return rosettaTx, nil
+}
+
+// extractTxIdentifiersFromBlock returns the identifiers of the transactions (and refund receipts) of a block, without transforming them.
+// Transactions that turn out to have no operations of interest are included, as well.
+func (transformer *transactionsTransformer) extractTxIdentifiersFromBlock(block *data.Block) ([]*types.TransactionIdentifier, error) {
+	prepared := transformer.prepareTxsOfBlock(block)
+	identifiers := make([]*types.TransactionIdentifier, 0, len(prepared.txs)+len(prepared.refundReceipts))
+
+	for _, tx := range prepared.txs {
+		identifiers = append(identifiers, hashToTransactionIdentifier(tx.Hash))
+	}
+
+	for _, receipt := range prepared.refundReceipts {
+		receiptHash, err := transformer.provider.ComputeReceiptHash(receipt)
 		if err != nil {
 			return nil, err
 		}
 
-		rosettaTxs = append(rosettaTxs, rosettaTx)
+		identifiers = append(identifiers, hashToTransactionIdentifier(receiptHash))
 	}
 
-	for _, receipt := range receipts {
-		if receipt.Data == refundGasMessage {
-			rosettaTx, err := transformer.refundReceiptToRosettaTx(receipt)
+	return identifiers, nil
+}
+
+// transformTxFromBlock transforms a single transaction (or refund receipt) of a block, the same way as transformTxsFromBlock() does.
+// The "transferValueOnly" events of the preceding transactions are replayed (but not transformed), so that the value movements
+// they cover aren't reported again. Returns false if the transaction is not in the block.
+func (transformer *transactionsTransformer) transformTxFromBlock(block *data.Block, txHash string) (*types.Transaction, bool, error) {
+	prepared := transformer.prepareTxsOfBlock(block)
+
+	for _, tx := range prepared.txs {
+		if tx.Hash != txHash {
+			err := transformer.addOperationsGivenEventsTransferValueOnly(tx, &types.Transaction{}, prepared.coveredValueMovements)
 			if err != nil {
-				return nil, err
+				return nil, false, err
 			}
 
-			rosettaTxs = append(rosettaTxs, rosettaTx)
+			continue
 		}
+
+		rosettaTx, err := transformer.transformTxOfBlock(tx, prepared)
+		if err != nil {
+			return nil, false, err
+		}
+
+		err = transformer.filterOperationsOfRosettaTx(rosettaTx)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return rosettaTx, true, nil
 	}
 
-	for _, rosettaTx := range rosettaTxs {
-		filteredOperations, err := transformer.extension.filterObservedOperations(rosettaTx.Operations)
+	for _, receipt := range prepared.refundReceipts {
+		rosettaTx, err := transformer.refundReceiptToRosettaTx(receipt)
 		if err != nil {
-			return nil, err
+			return nil, false, err
+		}
+		if rosettaTx.TransactionIdentifier.Hash != txHash {
+			continue
+		}
+
+		err = transformer.filterOperationsOfRosettaTx(rosettaTx)
+		if err != nil {
+			return nil, false, err
 		}
 
-		populateStatusOfOperations(filteredOperations)
-		rosettaTx.Operations = filteredOperations
+		return rosettaTx, true, nil
 	}
 
-	rosettaTxs = filterOutRosettaTransactionsWithNoOperations(rosettaTxs)
+	return nil, false, nil
+}
 
-	return rosettaTxs, nil
+// finalizeRosettaTx discards the operations that aren't of interest, and passes the transaction to the handler (if it still has operations)
+func (transformer *transactionsTransformer) finalizeRosettaTx(rosettaTx *types.Transaction, handleRosettaTx func(rosettaTx *types.Transaction)) error {
+	err := transformer.filterOperationsOfRosettaTx(rosettaTx)
+	if err != nil {
+		return err
+	}
+
+	if len(rosettaTx.Operations) > 0 {
+		handleRosettaTx(rosettaTx)
+	}
+
+	return nil
+}
+
+func (transformer *transactionsTransformer) filterOperationsOfRosettaTx(rosettaTx *types.Transaction) error {
+	filteredOperations, err := transformer.extension.filterObservedOperations(rosettaTx.Operations)
+	if err != nil {
+		return err
+	}
+
+	populateStatusOfOperations(filteredOperations)
+	rosettaTx.Operations = filteredOperations
+	return nil
 }
 
-func (transformer *transactionsTransformer) txToRosettaTx(tx *data.FullTransaction, txsInBlock []*data.FullTransaction) (*types.Transaction, error) {
+func (transformer *transactionsTransformer) txToRosettaTx(
+	tx *data.FullTransaction,
+	txsInBlock []*data.FullTransaction,
+	blockNonce uint64,
+	coveredValueMovements *valueMovementsIndex,
+) (*types.Transaction, error) {
 	var rosettaTx *types.Transaction
 
 	switch tx.Type {
@@ -98,7 +251,7 @@ func (transformer *transactionsTransformer) txToRosettaTx(tx *data.FullTransacti
 		return nil, fmt.Errorf("unknown transaction type: %s", tx.Type)
 	}
 
-	err := transformer.addOperationsGivenTransactionEvents(tx, rosettaTx)
+	err := transformer.addOperationsGivenTransactionEvents(tx, rosettaTx, blockNonce, coveredValueMovements)
 	if err != nil {
 		return nil, err
 	}
@@ -136,6 +289,14 @@ func (transformer *transactionsTransformer) unsignedTxToRosettaTx(
 		}
 	}
 
+	if isZeroOrNegativeValue(scr.Value) {
+		// Contract results that only carry token transfers (their operations are extracted from the events)
+		return &types.Transaction{
+			TransactionIdentifier: hashToTransactionIdentifier(scr.Hash),
+			Operations:            []*types.Operation{},
+		}
+	}
+
 	return &types.Transaction{
 		TransactionIdentifier: hashToTransactionIdentifier(scr.Hash),
 		Operations: []*types.Operation{
@@ -170,6 +331,11 @@ func (transformer *transactionsTransformer) moveBalanceTxToRosetta(tx *data.Full
 	hasValue := tx.Value != "0"
 	operations := make([]*types.Operation, 0)
 
+	receiver := tx.Receiver
+	if deployedContract, ok := transformer.featuresDetector.getAddressOfDeployedContract(tx); ok {
+		receiver = deployedContract
+	}
+
 	if hasValue {
 		operations = append(operations, &types.Operation{
 			Type:    opTransfer,
@@ -179,7 +345,7 @@ func (transformer *transactionsTransformer) moveBalanceTxToRosetta(tx *data.Full
 
 		operations = append(operations, &types.Operation{
 			Type:    opTransfer,
-			Account: addressToAccountIdentifier(tx.Receiver),
+			Account: addressToAccountIdentifier(receiver),
 			Amount:  transformer.extension.valueToNativeAmount(tx.Value),
 		})
 	}
@@ -237,7 +403,11 @@ func (transformer *transactionsTransformer) invalidTxToRosettaTx(tx *data.FullTr
 	}
 }
 
-func (transformer *transactionsTransformer) mempoolMoveBalanceTxToRosettaTx(tx *data.FullTransaction) *types.Transaction {
+// mempoolTxToRosettaTx predicts the operations of a transaction in the pool, so that they have the same shape as the ones returned by /block
+// (once the transaction is executed). Since the events aren't available yet, token transfers are decoded from the data field
+// (for relayed transactions, from the data field of the inner transaction). The fee is the one initially paid (before any refund).
+// The status of the operations is left unset.
+func (transformer *transactionsTransformer) mempoolTxToRosettaTx(tx *data.FullTransaction, blockNonce uint64) (*types.Transaction, error) {
 	hasValue := tx.Value != "0"
 	operations := make([]*types.Operation, 0)
 
@@ -255,37 +425,155 @@ func (transformer *transactionsTransformer) mempoolMoveBalanceTxToRosettaTx(tx *
 		})
 	}
 
-	indexOperations(operations)
+	operations = append(operations, &types.Operation{
+		Type:    opFee,
+		Account: addressToAccountIdentifier(tx.Sender),
+		Amount:  transformer.extension.valueToNativeAmount("-" + transformer.provider.ComputeTransactionFee(tx).String()),
+	})
+
+	tokensSender, tokensReceiver, dataField := tx.Sender, tx.Receiver, tx.Data
+	if inner, isRelayed := transformer.dataParser.parseRelayedTransaction(tx); isRelayed {
+		tokensSender, tokensReceiver, dataField = inner.sender, inner.receiver, inner.data
+
+		// Same as for /block, the inner transfer is reported on the relayed transaction.
+		if !isZeroOrNegativeValue(inner.value) {
+			operations = append(operations, transformer.innerTransferToOperations(inner.sender, inner.receiver, inner.value)...)
+		}
+	}
+
+	for _, event := range transformer.dataParser.parseTokenTransfers(tokensSender, tokensReceiver, dataField) {
+		currency, isSupported := transformer.extension.getActiveCustomCurrencyByIdentifier(event.getExtendedIdentifier(), blockNonce)
+		if !isSupported {
+			continue
+		}
+
+		operations = append(operations, transformer.eventESDTToOperations(event, currency)...)
+	}
+
+	filteredOperations, err := transformer.extension.filterObservedOperations(operations)
+	if err != nil {
+		return nil, err
+	}
 
 	return &types.Transaction{
 		TransactionIdentifier: hashToTransactionIdentifier(tx.Hash),
-		Operations:            operations,
+		Operations:            filteredOperations,
+	}, nil
+}
+
+// addOperationsGivenRelayedInnerTransfer emits the value movement of the inner transaction (from the inner sender to the inner receiver)
+// on the relayed transaction itself, instead of on the contract result that carries it.
+func (transformer *transactionsTransformer) addOperationsGivenRelayedInnerTransfer(innerTransfer *data.FullTransaction, rosettaTx *types.Transaction) {
+	rosettaTx.Operations = append(rosettaTx.Operations, transformer.innerTransferToOperations(innerTransfer.Sender, innerTransfer.Receiver, innerTransfer.Value)...)
+}
+
+func (transformer *transactionsTransformer) innerTransferToOperations(sender string, receiver string, value string) []*types.Operation {
+	return []*types.Operation{
+		{
+			Type:    opTransfer,
+			Account: addressToAccountIdentifier(sender),
+			Amount:  transformer.extension.valueToNativeAmount("-" + value),
+		},
+		{
+			Type:    opTransfer,
+			Account: addressToAccountIdentifier(receiver),
+			Amount:  transformer.extension.valueToNativeAmount(value),
+		},
 	}
 }
 
-func (transformer *transactionsTransformer) addOperationsGivenTransactionEvents(tx *data.FullTransaction, rosettaTx *types.Transaction) error {
-	// TBD: uncomment when applicable ("transferValueOnly" events duplicate the information of SCRs in most contexts)
-	// err := transformer.addOperationsGivenEventTransferValueOnly(tx, rosettaTx)
-	// if err != nil {
-	// 	return err
-	// }
+func (transformer *transactionsTransformer) addOperationsGivenTransactionEvents(
+	tx *data.FullTransaction,
+	rosettaTx *types.Transaction,
+	blockNonce uint64,
+	coveredValueMovements *valueMovementsIndex,
+) error {
+	err := transformer.addOperationsGivenEventsTransferValueOnly(tx, rosettaTx, coveredValueMovements)
+	if err != nil {
+		return err
+	}
+
+	err = transformer.addOperationsGivenEventsESDTTransfers(tx, rosettaTx, blockNonce)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
 
-func (transformer *transactionsTransformer) addOperationsGivenEventTransferValueOnly(tx *data.FullTransaction, rosettaTx *types.Transaction) error {
-	event, err := transformer.eventsController.extractEventTransferValueOnly(tx)
+// addOperationsGivenEventsESDTTransfers emits both the debit and the credit of each token transfer. For cross-shard transfers,
+// the source shard holds the event on the transaction, while the destination shard holds it on the contract result;
+// operations affecting accounts outside the observed shard are discarded afterwards, by filterObservedOperations().
+// Transfers of tokens not in the registry (or not yet active at the given block) are skipped.
+func (transformer *transactionsTransformer) addOperationsGivenEventsESDTTransfers(
+	tx *data.FullTransaction,
+	rosettaTx *types.Transaction,
+	blockNonce uint64,
+) error {
+	events, err := transformer.eventsController.extractEventsESDTTransfers(tx)
 	if err != nil {
-		if errors.Is(err, errEventNotFound) {
-			return nil
+		return err
+	}
+
+	for _, event := range events {
+		currency, isSupported := transformer.extension.getActiveCustomCurrencyByIdentifier(event.getExtendedIdentifier(), blockNonce)
+		if !isSupported {
+			log.Trace("addOperationsGivenEventsESDTTransfers(), unsupported currency", "tx", tx.Hash, "event", event.String())
+			continue
 		}
+
+		log.Debug("addOperationsGivenEventsESDTTransfers(), event found", "tx", tx.Hash, "event", event.String())
+
+		operations := transformer.eventESDTToOperations(event, currency)
+		rosettaTx.Operations = append(rosettaTx.Operations, operations...)
+	}
+
+	return nil
+}
+
+func (transformer *transactionsTransformer) eventESDTToOperations(event *eventESDT, currency resources.CustomCurrency) []*types.Operation {
+	return []*types.Operation{
+		{
+			Type:    opTransfer,
+			Account: addressToAccountIdentifier(event.sender),
+			Amount:  transformer.extension.valueToCustomAmount("-"+event.value, currency),
+		},
+		{
+			Type:    opTransfer,
+			Account: addressToAccountIdentifier(event.receiver),
+			Amount:  transformer.extension.valueToCustomAmount(event.value, currency),
+		},
+	}
+}
+
+// addOperationsGivenEventsTransferValueOnly emits operations for "transferValueOnly" events, but only for the value movements
+// that aren't already covered by a transaction or a contract result of the block (in order to avoid double counting).
+func (transformer *transactionsTransformer) addOperationsGivenEventsTransferValueOnly(
+	tx *data.FullTransaction,
+	rosettaTx *types.Transaction,
+	coveredValueMovements *valueMovementsIndex,
+) error {
+	events, err := transformer.eventsController.extractEventsTransferValueOnly(tx)
+	if err != nil {
 		return err
 	}
 
-	log.Debug("addOperationsGivenEventTransferValueOnly(), event found", "tx", tx.Hash, "event", event.String())
+	for _, event := range events {
+		if isZeroOrNegativeValue(event.value) {
+			continue
+		}
+
+		if coveredValueMovements.consume(event.toValueMovement(getOriginalTransactionHash(tx))) {
+			log.Trace("addOperationsGivenEventsTransferValueOnly(), event already covered", "tx", tx.Hash, "event", event.String())
+			continue
+		}
+
+		log.Debug("addOperationsGivenEventsTransferValueOnly(), event found", "tx", tx.Hash, "event", event.String())
+
+		operations := transformer.eventTransferValueOnlyToOperations(event)
+		rosettaTx.Operations = append(rosettaTx.Operations, operations...)
+	}
 
-	operations := transformer.eventTransferValueOnlyToOperations(event)
-	rosettaTx.Operations = append(rosettaTx.Operations, operations...)
 	return nil
 }
 
@@ -1,9 +1,15 @@
 package services
 
 import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
 	"testing"
 
+	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
 	"github.com/ElrondNetwork/elrond-proxy-go/data"
+	"github.com/ElrondNetwork/rosetta/server/resources"
 	"github.com/ElrondNetwork/rosetta/testscommon"
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/stretchr/testify/require"
@@ -65,3 +71,521 @@ func TestTransactionsTransformer_UnsignedTxToRosettaTx(t *testing.T) {
 	require.Equal(t, expectedRefundTx, rosettaFefundTx)
 	require.Equal(t, expectedMoveBalanceTx, rosettaMoveBalanceTx)
 }
+
+func TestTransactionsTransformer_TransformTxsOfBlockWithESDTTransfers(t *testing.T) {
+	networkProvider := testscommon.NewNetworkProviderMock()
+	networkProvider.MockNumShards = 1
+	networkProvider.MockCustomCurrencies = []resources.CustomCurrency{
+		{Identifier: "ROSETTA-3a2edf", Symbol: "ROSETTA", Decimals: 2},
+		{Identifier: "FUTURE-abcdef", Symbol: "FUTURE", Decimals: 2, ActivationNonce: 43},
+	}
+	extension := newNetworkProviderExtension(networkProvider)
+	transformer := newTransactionsTransformer(networkProvider)
+	currency := networkProvider.MockCustomCurrencies[0]
+
+	transfer := &data.FullTransaction{
+		Hash:             "aaaa",
+		Type:             string(transaction.TxTypeNormal),
+		Sender:           testscommon.TestAddressAlice,
+		Receiver:         testscommon.TestAddressBob,
+		Value:            "0",
+		Data:             []byte("ESDTTransfer@524f53455454412d336132656466@64"),
+		InitiallyPaidFee: "50000000000000",
+		Logs: &transaction.ApiLogs{
+			Events: []*transaction.Events{
+				{
+					Address:    testscommon.TestAddressAlice,
+					Identifier: transactionEventESDTTransfer,
+					Topics:     [][]byte{[]byte("ROSETTA-3a2edf"), {}, big.NewInt(100).Bytes(), testscommon.TestPubKeyBob},
+				},
+			},
+		},
+	}
+
+	// E.g. the contract result of a cross-shard transfer, on the destination shard
+	contractResult := &data.FullTransaction{
+		Hash:     "bbbb",
+		Type:     string(transaction.TxTypeUnsigned),
+		Sender:   testscommon.TestAddressBob,
+		Receiver: testscommon.TestAddressAlice,
+		Value:    "0",
+		Logs: &transaction.ApiLogs{
+			Events: []*transaction.Events{
+				{
+					Address:    testscommon.TestAddressBob,
+					Identifier: transactionEventMultiESDTNFTTransfer,
+					Topics:     [][]byte{[]byte("ROSETTA-3a2edf"), {}, big.NewInt(42).Bytes(), testscommon.TestPubKeyAlice},
+				},
+				{
+					Address:    testscommon.TestAddressBob,
+					Identifier: transactionEventMultiESDTNFTTransfer,
+					Topics:     [][]byte{[]byte("UNKNOWN-abcdef"), {}, big.NewInt(43).Bytes(), testscommon.TestPubKeyAlice},
+				},
+				{
+					// Not yet active (at the given block)
+					Address:    testscommon.TestAddressBob,
+					Identifier: transactionEventMultiESDTNFTTransfer,
+					Topics:     [][]byte{[]byte("FUTURE-abcdef"), {}, big.NewInt(44).Bytes(), testscommon.TestPubKeyAlice},
+				},
+			},
+		},
+	}
+
+	block := &data.Block{
+		Nonce: 42,
+		MiniBlocks: []*data.MiniBlock{
+			{Transactions: []*data.FullTransaction{transfer, contractResult}},
+		},
+	}
+
+	rosettaTxs, err := transformer.transformTxsFromBlock(block)
+	require.Nil(t, err)
+	require.Len(t, rosettaTxs, 2)
+
+	require.Equal(t, []*types.Operation{
+		{
+			OperationIdentifier: indexToOperationIdentifier(0),
+			Type:                opFee,
+			Account:             addressToAccountIdentifier(testscommon.TestAddressAlice),
+			Amount:              extension.valueToNativeAmount("-50000000000000"),
+			Status:              &opStatusSuccess,
+		},
+		{
+			OperationIdentifier: indexToOperationIdentifier(1),
+			Type:                opTransfer,
+			Account:             addressToAccountIdentifier(testscommon.TestAddressAlice),
+			Amount:              extension.valueToCustomAmount("-100", currency),
+			Status:              &opStatusSuccess,
+		},
+		{
+			OperationIdentifier: indexToOperationIdentifier(2),
+			Type:                opTransfer,
+			Account:             addressToAccountIdentifier(testscommon.TestAddressBob),
+			Amount:              extension.valueToCustomAmount("100", currency),
+			Status:              &opStatusSuccess,
+		},
+	}, rosettaTxs[0].Operations)
+
+	require.Equal(t, []*types.Operation{
+		{
+			OperationIdentifier: indexToOperationIdentifier(0),
+			Type:                opTransfer,
+			Account:             addressToAccountIdentifier(testscommon.TestAddressBob),
+			Amount:              extension.valueToCustomAmount("-42", currency),
+			Status:              &opStatusSuccess,
+		},
+		{
+			OperationIdentifier: indexToOperationIdentifier(1),
+			Type:                opTransfer,
+			Account:             addressToAccountIdentifier(testscommon.TestAddressAlice),
+			Amount:              extension.valueToCustomAmount("42", currency),
+			Status:              &opStatusSuccess,
+		},
+	}, rosettaTxs[1].Operations)
+}
+
+func TestTransactionsTransformer_TransformTxsOfBlockWithEventsTransferValueOnly(t *testing.T) {
+	networkProvider := testscommon.NewNetworkProviderMock()
+	networkProvider.MockNumShards = 1
+	transformer := newTransactionsTransformer(networkProvider)
+
+	// Alice calls a contract (with value), which, in turn, sends value to Bob (within an intra-shard async call).
+	// The transfer to the contract is covered by the transaction itself, thus its event must be ignored.
+	newCall := func() *data.FullTransaction {
+		return &data.FullTransaction{
+			Hash:             "aaaa",
+			Type:             string(transaction.TxTypeNormal),
+			Sender:           testscommon.TestAddressAlice,
+			Receiver:         testscommon.TestAddressOfContract,
+			Value:            "5000",
+			Data:             []byte("doSomething"),
+			InitiallyPaidFee: "50000000000000",
+			Logs: &transaction.ApiLogs{
+				Events: []*transaction.Events{
+					{
+						Address:    testscommon.TestAddressOfContract,
+						Identifier: transactionEventTransferValueOnly,
+						Topics:     [][]byte{testscommon.TestPubKeyAlice, testscommon.TestPubkeyOfContract, big.NewInt(5000).Bytes()},
+					},
+					{
+						Address:    testscommon.TestAddressOfContract,
+						Identifier: transactionEventTransferValueOnly,
+						Topics:     [][]byte{testscommon.TestPubkeyOfContract, testscommon.TestPubKeyBob, big.NewInt(1000).Bytes()},
+					},
+				},
+			},
+		}
+	}
+
+	expectedBalanceChanges := map[string]string{
+		testscommon.TestAddressAlice: "-50000000005000",
+		testscommon.TestAddressBob:   "1000",
+	}
+
+	t.Run("when no contract result covers the transfer to Bob", func(t *testing.T) {
+		block := &data.Block{
+			MiniBlocks: []*data.MiniBlock{
+				{Transactions: []*data.FullTransaction{newCall()}},
+			},
+		}
+
+		rosettaTxs, err := transformer.transformTxsFromBlock(block)
+		require.Nil(t, err)
+		require.Len(t, rosettaTxs, 1)
+		require.Len(t, rosettaTxs[0].Operations, 3)
+		require.Equal(t, expectedBalanceChanges, computeBalanceChangesGivenRosettaTxs(rosettaTxs))
+	})
+
+	t.Run("when a contract result covers the transfer to Bob", func(t *testing.T) {
+		contractResult := &data.FullTransaction{
+			Hash:                    "bbbb",
+			Type:                    string(transaction.TxTypeUnsigned),
+			Sender:                  testscommon.TestAddressOfContract,
+			Receiver:                testscommon.TestAddressBob,
+			Value:                   "1000",
+			OriginalTransactionHash: "aaaa",
+		}
+
+		block := &data.Block{
+			MiniBlocks: []*data.MiniBlock{
+				{Transactions: []*data.FullTransaction{newCall(), contractResult}},
+			},
+		}
+
+		rosettaTxs, err := transformer.transformTxsFromBlock(block)
+		require.Nil(t, err)
+		require.Len(t, rosettaTxs, 2)
+		require.Len(t, rosettaTxs[0].Operations, 2)
+		require.Len(t, rosettaTxs[1].Operations, 1)
+		require.Equal(t, opScResult, rosettaTxs[1].Operations[0].Type)
+		require.Equal(t, expectedBalanceChanges, computeBalanceChangesGivenRosettaTxs(rosettaTxs))
+	})
+
+	t.Run("when an unrelated contract result (with the same sender, receiver and value) is in the block", func(t *testing.T) {
+		unrelatedContractResult := &data.FullTransaction{
+			Hash:                    "cccc",
+			Type:                    string(transaction.TxTypeUnsigned),
+			Sender:                  testscommon.TestAddressOfContract,
+			Receiver:                testscommon.TestAddressBob,
+			Value:                   "1000",
+			OriginalTransactionHash: "dddd",
+		}
+
+		block := &data.Block{
+			MiniBlocks: []*data.MiniBlock{
+				{Transactions: []*data.FullTransaction{newCall(), unrelatedContractResult}},
+			},
+		}
+
+		rosettaTxs, err := transformer.transformTxsFromBlock(block)
+		require.Nil(t, err)
+		require.Len(t, rosettaTxs, 2)
+		require.Len(t, rosettaTxs[0].Operations, 3)
+		require.Len(t, rosettaTxs[1].Operations, 1)
+		require.Equal(t, map[string]string{
+			testscommon.TestAddressAlice: "-50000000005000",
+			testscommon.TestAddressBob:   "2000",
+		}, computeBalanceChangesGivenRosettaTxs(rosettaTxs))
+	})
+
+	t.Run("when the transfer to Bob is signaled by a contract result with no value", func(t *testing.T) {
+		// E.g. the callback of an async call, executed on the shard of the contract, which sends value to Bob
+		call := newCall()
+		call.Logs.Events = call.Logs.Events[:1]
+
+		callback := &data.FullTransaction{
+			Hash:                    "bbbb",
+			Type:                    string(transaction.TxTypeUnsigned),
+			Sender:                  testscommon.TestAddressOfContract,
+			Receiver:                testscommon.TestAddressOfContract,
+			Value:                   "0",
+			OriginalTransactionHash: "aaaa",
+			Logs: &transaction.ApiLogs{
+				Events: []*transaction.Events{
+					{
+						Address:    testscommon.TestAddressOfContract,
+						Identifier: transactionEventTransferValueOnly,
+						Topics:     [][]byte{testscommon.TestPubkeyOfContract, testscommon.TestPubKeyBob, big.NewInt(1000).Bytes()},
+					},
+				},
+			},
+		}
+
+		block := &data.Block{
+			MiniBlocks: []*data.MiniBlock{
+				{Transactions: []*data.FullTransaction{call, callback}},
+			},
+		}
+
+		rosettaTxs, err := transformer.transformTxsFromBlock(block)
+		require.Nil(t, err)
+		require.Len(t, rosettaTxs, 2)
+		require.Equal(t, "bbbb", rosettaTxs[1].TransactionIdentifier.Hash)
+		require.Len(t, rosettaTxs[1].Operations, 1)
+		require.Equal(t, expectedBalanceChanges, computeBalanceChangesGivenRosettaTxs(rosettaTxs))
+	})
+}
+
+func computeBalanceChangesGivenRosettaTxs(rosettaTxs []*types.Transaction) map[string]string {
+	changes := make(map[string]*big.Int)
+
+	for _, rosettaTx := range rosettaTxs {
+		for _, operation := range rosettaTx.Operations {
+			address := operation.Account.Address
+			value, _ := big.NewInt(0).SetString(operation.Amount.Value, 10)
+
+			if _, ok := changes[address]; !ok {
+				changes[address] = big.NewInt(0)
+			}
+
+			changes[address].Add(changes[address], value)
+		}
+	}
+
+	changesAsStrings := make(map[string]string, len(changes))
+	for address, change := range changes {
+		changesAsStrings[address] = change.String()
+	}
+
+	return changesAsStrings
+}
+
+func TestTransactionsTransformer_TransformTxsOfBlockWithTrackedContracts(t *testing.T) {
+	deploymentAddress := "erd1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq6gq4hu"
+
+	deployment := &data.FullTransaction{
+		Hash:             "aaaa",
+		Type:             string(transaction.TxTypeNormal),
+		Sender:           testscommon.TestAddressAlice,
+		Receiver:         deploymentAddress,
+		Value:            "1000",
+		InitiallyPaidFee: "50000",
+		Logs: &transaction.ApiLogs{
+			Events: []*transaction.Events{
+				{
+					Address:    testscommon.TestAddressOfContract,
+					Identifier: transactionEventSCDeploy,
+					Topics:     [][]byte{testscommon.TestPubkeyOfContract, testscommon.TestPubKeyAlice},
+				},
+				{
+					Address:    testscommon.TestAddressOfContract,
+					Identifier: transactionEventTransferValueOnly,
+					Topics:     [][]byte{testscommon.TestPubKeyAlice, testscommon.TestPubkeyOfContract, big.NewInt(1000).Bytes()},
+				},
+			},
+		},
+	}
+
+	payableCall := &data.FullTransaction{
+		Hash:             "bbbb",
+		Type:             string(transaction.TxTypeNormal),
+		Sender:           testscommon.TestAddressAlice,
+		Receiver:         testscommon.TestAddressOfContract,
+		Value:            "500",
+		Data:             []byte("deposit"),
+		InitiallyPaidFee: "50000",
+	}
+
+	callback := &data.FullTransaction{
+		Hash:                    "cccc",
+		Type:                    string(transaction.TxTypeUnsigned),
+		Sender:                  testscommon.TestAddressOfContract,
+		Receiver:                testscommon.TestAddressAlice,
+		Value:                   "200",
+		Nonce:                   1,
+		OriginalTransactionHash: "bbbb",
+	}
+
+	claimDeveloperRewards := &data.FullTransaction{
+		Hash:                        "dddd",
+		Type:                        string(transaction.TxTypeNormal),
+		Sender:                      testscommon.TestAddressAlice,
+		Receiver:                    testscommon.TestAddressOfContract,
+		Value:                       "0",
+		Data:                        []byte(builtInFunctionClaimDeveloperRewards),
+		InitiallyPaidFee:            "50000",
+		ProcessingTypeOnSource:      transactionProcessingTypeBuiltInFunctionCall,
+		ProcessingTypeOnDestination: transactionProcessingTypeBuiltInFunctionCall,
+	}
+
+	// Developer rewards aren't taken from the balance of the contract.
+	developerRewards := &data.FullTransaction{
+		Hash:                    "eeee",
+		Type:                    string(transaction.TxTypeUnsigned),
+		Sender:                  testscommon.TestAddressOfContract,
+		Receiver:                testscommon.TestAddressAlice,
+		Value:                   "300",
+		OriginalTransactionHash: "dddd",
+	}
+
+	block := &data.Block{
+		MiniBlocks: []*data.MiniBlock{
+			{Transactions: []*data.FullTransaction{deployment, payableCall, callback, claimDeveloperRewards, developerRewards}},
+		},
+	}
+
+	t.Run("when contract is not tracked", func(t *testing.T) {
+		networkProvider := testscommon.NewNetworkProviderMock()
+		networkProvider.MockNumShards = 1
+		transformer := newTransactionsTransformer(networkProvider)
+
+		rosettaTxs, err := transformer.transformTxsFromBlock(block)
+		require.Nil(t, err)
+		require.Equal(t, map[string]string{
+			testscommon.TestAddressAlice: "-151000",
+		}, computeBalanceChangesGivenRosettaTxs(rosettaTxs))
+	})
+
+	t.Run("when contract is tracked", func(t *testing.T) {
+		networkProvider := testscommon.NewNetworkProviderMock()
+		networkProvider.MockNumShards = 1
+		networkProvider.MockTrackedContracts[testscommon.TestAddressOfContract] = struct{}{}
+		transformer := newTransactionsTransformer(networkProvider)
+
+		rosettaTxs, err := transformer.transformTxsFromBlock(block)
+		require.Nil(t, err)
+		require.Equal(t, map[string]string{
+			testscommon.TestAddressAlice:      "-151000",
+			testscommon.TestAddressOfContract: "1300",
+		}, computeBalanceChangesGivenRosettaTxs(rosettaTxs))
+	})
+}
+
+func TestTransactionsTransformer_TransformTxsOfBlockWithRelayedTransactions(t *testing.T) {
+	relayer := newTestAddressInShard(0xaa, 1)
+	innerSender := newTestAddressInShard(0xbb, 0)
+	innerReceiver := newTestAddressInShard(0xcc, 2)
+
+	innerTxJson, err := json.Marshal(&transaction.Transaction{
+		Nonce:   7,
+		Value:   big.NewInt(1000),
+		SndAddr: decodeTestAddress(innerSender),
+		RcvAddr: decodeTestAddress(innerReceiver),
+	})
+	require.Nil(t, err)
+
+	relayedTx := &data.FullTransaction{
+		Hash:             "aaaa",
+		Type:             string(transaction.TxTypeNormal),
+		Sender:           relayer,
+		Receiver:         innerSender,
+		Value:            "1000",
+		Data:             []byte("relayedTx@" + hex.EncodeToString(innerTxJson)),
+		InitiallyPaidFee: "100000",
+	}
+
+	// The result of executing the inner transaction (on the shard of the inner sender)
+	innerContractResult := &data.FullTransaction{
+		Hash:                    "bbbb",
+		Type:                    string(transaction.TxTypeUnsigned),
+		Sender:                  innerSender,
+		Receiver:                innerReceiver,
+		Value:                   "1000",
+		OriginalTransactionHash: "aaaa",
+	}
+
+	t.Run("intra-shard", func(t *testing.T) {
+		networkProvider := testscommon.NewNetworkProviderMock()
+		networkProvider.MockNumShards = 1
+		transformer := newTransactionsTransformer(networkProvider)
+
+		block := &data.Block{
+			MiniBlocks: []*data.MiniBlock{
+				{Transactions: []*data.FullTransaction{relayedTx, innerContractResult}},
+			},
+		}
+
+		rosettaTxs, err := transformer.transformTxsFromBlock(block)
+		require.Nil(t, err)
+		require.Len(t, rosettaTxs, 1)
+		require.Equal(t, "aaaa", rosettaTxs[0].TransactionIdentifier.Hash)
+
+		operations := rosettaTxs[0].Operations
+		require.Len(t, operations, 5)
+		require.Equal(t, opFee, operations[2].Type)
+		require.Equal(t, relayer, operations[2].Account.Address)
+		require.Equal(t, innerSender, operations[3].Account.Address)
+		require.Equal(t, "-1000", operations[3].Amount.Value)
+		require.Equal(t, innerReceiver, operations[4].Account.Address)
+		require.Equal(t, "1000", operations[4].Amount.Value)
+
+		require.Equal(t, map[string]string{
+			relayer:       "-101000",
+			innerSender:   "0",
+			innerReceiver: "1000",
+		}, computeBalanceChangesGivenRosettaTxs(rosettaTxs))
+	})
+
+	t.Run("cross-shard, on the shard of the relayer", func(t *testing.T) {
+		networkProvider := testscommon.NewNetworkProviderMock()
+		networkProvider.MockObservedActualShard = 1
+		transformer := newTransactionsTransformer(networkProvider)
+
+		block := &data.Block{
+			MiniBlocks: []*data.MiniBlock{
+				{Transactions: []*data.FullTransaction{relayedTx}},
+			},
+		}
+
+		rosettaTxs, err := transformer.transformTxsFromBlock(block)
+		require.Nil(t, err)
+		require.Len(t, rosettaTxs, 1)
+		require.Equal(t, map[string]string{
+			relayer: "-101000",
+		}, computeBalanceChangesGivenRosettaTxs(rosettaTxs))
+	})
+
+	t.Run("cross-shard, on the shard of the inner sender", func(t *testing.T) {
+		networkProvider := testscommon.NewNetworkProviderMock()
+		networkProvider.MockObservedActualShard = 0
+		transformer := newTransactionsTransformer(networkProvider)
+
+		block := &data.Block{
+			MiniBlocks: []*data.MiniBlock{
+				{Transactions: []*data.FullTransaction{relayedTx}},
+				{Transactions: []*data.FullTransaction{innerContractResult}},
+			},
+		}
+
+		rosettaTxs, err := transformer.transformTxsFromBlock(block)
+		require.Nil(t, err)
+		require.Len(t, rosettaTxs, 1)
+		require.Equal(t, "aaaa", rosettaTxs[0].TransactionIdentifier.Hash)
+		require.Len(t, rosettaTxs[0].Operations, 2)
+		require.Equal(t, map[string]string{
+			innerSender: "0",
+		}, computeBalanceChangesGivenRosettaTxs(rosettaTxs))
+	})
+
+	t.Run("cross-shard, on the shard of the inner receiver", func(t *testing.T) {
+		networkProvider := testscommon.NewNetworkProviderMock()
+		networkProvider.MockObservedActualShard = 2
+		transformer := newTransactionsTransformer(networkProvider)
+
+		block := &data.Block{
+			MiniBlocks: []*data.MiniBlock{
+				{Transactions: []*data.FullTransaction{innerContractResult}},
+			},
+		}
+
+		rosettaTxs, err := transformer.transformTxsFromBlock(block)
+		require.Nil(t, err)
+		require.Len(t, rosettaTxs, 1)
+		require.Equal(t, "bbbb", rosettaTxs[0].TransactionIdentifier.Hash)
+		require.Equal(t, map[string]string{
+			innerReceiver: "1000",
+		}, computeBalanceChangesGivenRosettaTxs(rosettaTxs))
+	})
+}
+
+// newTestAddressInShard creates a (user) address that belongs to the given shard (in a setup with 3 shards)
+func newTestAddressInShard(seed byte, shard byte) string {
+	pubkey := bytes.Repeat([]byte{seed}, 32)
+	pubkey[31] = shard
+	return testscommon.RealWorldBech32PubkeyConverter.Encode(pubkey)
+}
+
+func decodeTestAddress(address string) []byte {
+	pubkey, _ := testscommon.RealWorldBech32PubkeyConverter.Decode(address)
+	return pubkey
+}
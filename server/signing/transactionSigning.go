@@ -0,0 +1,112 @@
+package signing
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/ElrondNetwork/elrond-go-core/core"
+	"github.com/ElrondNetwork/elrond-go-core/core/versioning"
+	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
+	"github.com/ElrondNetwork/elrond-go-core/hashing"
+	hasherFactory "github.com/ElrondNetwork/elrond-go-core/hashing/factory"
+	"github.com/ElrondNetwork/elrond-go-core/marshal"
+	marshalFactory "github.com/ElrondNetwork/elrond-go-core/marshal/factory"
+	"github.com/ElrondNetwork/elrond-proxy-go/data"
+)
+
+// Defined by the Network:
+var txSignHasherType = "keccak"
+var txSignMarshalizerType = "json"
+var minTransactionVersion uint32 = 1
+
+type txVersionChecker interface {
+	IsSignedWithHash(tx *transaction.Transaction) bool
+}
+
+// TransactionSigningPayloadComputer computes the bytes to be signed for a transaction, the same way the Node does
+// when verifying the signature: the canonical (JSON) serialization of the transaction or, for transactions signed by hash, its hash.
+type TransactionSigningPayloadComputer struct {
+	pubKeyConverter   core.PubkeyConverter
+	txSignHasher      hashing.Hasher
+	txSignMarshalizer marshal.Marshalizer
+	txVersionChecker  txVersionChecker
+}
+
+// NewTransactionSigningPayloadComputer creates a new TransactionSigningPayloadComputer
+func NewTransactionSigningPayloadComputer(pubKeyConverter core.PubkeyConverter) (*TransactionSigningPayloadComputer, error) {
+	txSignHasher, err := hasherFactory.NewHasher(txSignHasherType)
+	if err != nil {
+		return nil, err
+	}
+
+	txSignMarshalizer, err := marshalFactory.NewMarshalizer(txSignMarshalizerType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TransactionSigningPayloadComputer{
+		pubKeyConverter:   pubKeyConverter,
+		txSignHasher:      txSignHasher,
+		txSignMarshalizer: txSignMarshalizer,
+		txVersionChecker:  versioning.NewTxVersionChecker(minTransactionVersion),
+	}, nil
+}
+
+// ComputeSigningPayload computes the bytes to be signed for a provided transaction
+func (computer *TransactionSigningPayloadComputer) ComputeSigningPayload(tx *data.Transaction) ([]byte, error) {
+	protocolTx, err := ToProtocolTransaction(tx, computer.pubKeyConverter)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := protocolTx.GetDataForSigning(computer.pubKeyConverter, computer.txSignMarshalizer)
+	if err != nil {
+		return nil, err
+	}
+
+	if computer.txVersionChecker.IsSignedWithHash(protocolTx) {
+		return computer.txSignHasher.Compute(string(payload)), nil
+	}
+
+	return payload, nil
+}
+
+// ToProtocolTransaction converts a transaction (as received by the Construction API) into a protocol transaction
+func ToProtocolTransaction(tx *data.Transaction, pubKeyConverter core.PubkeyConverter) (*transaction.Transaction, error) {
+	value, ok := big.NewInt(0).SetString(tx.Value, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid value: %s", tx.Value)
+	}
+
+	receiverPubkey, err := pubKeyConverter.Decode(tx.Receiver)
+	if err != nil {
+		return nil, err
+	}
+
+	senderPubkey, err := pubKeyConverter.Decode(tx.Sender)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := hex.DecodeString(tx.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transaction.Transaction{
+		Nonce:       tx.Nonce,
+		Value:       value,
+		RcvAddr:     receiverPubkey,
+		RcvUserName: tx.ReceiverUsername,
+		SndAddr:     senderPubkey,
+		SndUserName: tx.SenderUsername,
+		GasPrice:    tx.GasPrice,
+		GasLimit:    tx.GasLimit,
+		Data:        tx.Data,
+		ChainID:     []byte(tx.ChainID),
+		Version:     tx.Version,
+		Signature:   signature,
+		Options:     tx.Options,
+	}, nil
+}
@@ -0,0 +1,82 @@
+package signing_test
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ElrondNetwork/elrond-go-core/core/pubkeyConverter"
+	logger "github.com/ElrondNetwork/elrond-go-logger"
+	"github.com/ElrondNetwork/elrond-proxy-go/data"
+	"github.com/ElrondNetwork/rosetta/server/signing"
+	"github.com/ElrondNetwork/rosetta/testscommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionSigningPayloadComputer_ComputeSigningPayload(t *testing.T) {
+	pubKeyConverter, _ := pubkeyConverter.NewBech32PubkeyConverter(32, logger.GetOrCreate("signing"))
+	computer, err := signing.NewTransactionSigningPayloadComputer(pubKeyConverter)
+	require.Nil(t, err)
+
+	alicePublicKey := testscommon.GetPublicKeyOfTestSecretKey(testscommon.TestSecretKeyAlice)
+
+	createTx := func() *data.Transaction {
+		return &data.Transaction{
+			Nonce:    42,
+			Value:    "1000000000000000000",
+			Receiver: testscommon.TestAddressBob,
+			Sender:   testscommon.TestAddressAlice,
+			GasPrice: 1000000000,
+			GasLimit: 50000,
+			ChainID:  "1",
+			Version:  1,
+		}
+	}
+
+	t.Run("signature verifies against the payload, but not against the one of an altered transaction", func(t *testing.T) {
+		payload, err := computer.ComputeSigningPayload(createTx())
+		require.Nil(t, err)
+		require.Equal(t, `{"nonce":42,"value":"1000000000000000000","receiver":"erd1spyavw0956vq68xj8y4tenjpq2wd5a9p2c6j8gsz7ztyrnpxrruqzu66jx","sender":"erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th","gasPrice":1000000000,"gasLimit":50000,"chainID":"1","version":1}`, string(payload))
+
+		signature := testscommon.SignWithTestSecretKey(testscommon.TestSecretKeyAlice, payload)
+		require.True(t, ed25519.Verify(alicePublicKey, payload, signature))
+
+		alteredTx := createTx()
+		alteredTx.Value = "1000000000000000001"
+		alteredPayload, err := computer.ComputeSigningPayload(alteredTx)
+		require.Nil(t, err)
+		require.False(t, ed25519.Verify(alicePublicKey, alteredPayload, signature))
+	})
+
+	t.Run("the signature of the transaction is not part of the payload", func(t *testing.T) {
+		payload, err := computer.ComputeSigningPayload(createTx())
+		require.Nil(t, err)
+
+		signedTx := createTx()
+		signedTx.Signature = hex.EncodeToString(testscommon.SignWithTestSecretKey(testscommon.TestSecretKeyAlice, payload))
+		payloadOfSignedTx, err := computer.ComputeSigningPayload(signedTx)
+		require.Nil(t, err)
+		require.Equal(t, payload, payloadOfSignedTx)
+	})
+
+	t.Run("signed by hash", func(t *testing.T) {
+		tx := createTx()
+		tx.Version = 2
+		tx.Options = 1
+
+		payload, err := computer.ComputeSigningPayload(tx)
+		require.Nil(t, err)
+		require.Len(t, payload, 32)
+
+		signature := testscommon.SignWithTestSecretKey(testscommon.TestSecretKeyAlice, payload)
+		require.True(t, ed25519.Verify(alicePublicKey, payload, signature))
+	})
+
+	t.Run("with bad value", func(t *testing.T) {
+		tx := createTx()
+		tx.Value = "foobar"
+
+		_, err := computer.ComputeSigningPayload(tx)
+		require.NotNil(t, err)
+	})
+}
@@ -25,6 +25,11 @@ var (
 	// TestPubKeyHexBob is a test pubkey
 	TestPubKeyHexBob = hex.EncodeToString(TestPubKeyBob)
 
+	// TestAddressCarol is a test address
+	TestAddressCarol = "erd1k2s324ww2g0yj38qn2ch2jwctdy8mnfxep94q9arncc6xecg3xaq6mjse8"
+	// TestPubKeyCarol is a test pubkey
+	TestPubKeyCarol, _ = RealWorldBech32PubkeyConverter.Decode(TestAddressCarol)
+
 	// TestAddressOfContract is a test address
 	TestAddressOfContract = "erd1qqqqqqqqqqqqqpgqfejaxfh4ktp8mh8s77pl90dq0uzvh2vk396qlcwepw"
 	// TestPubkeyOfContract is a test pubkey
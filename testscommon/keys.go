@@ -0,0 +1,26 @@
+package testscommon
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+)
+
+// Secret keys of the (publicly known) test wallets, as found in the PEM files used by localnets and devnets
+var (
+	// TestSecretKeyAlice is the secret key of TestAddressAlice
+	TestSecretKeyAlice, _ = hex.DecodeString("413f42575f7f26fad3317a778771212fdb80245850981e48b58a4f25e344e8f9")
+	// TestSecretKeyBob is the secret key of TestAddressBob
+	TestSecretKeyBob, _ = hex.DecodeString("b8ca6f8203fb4b545a8e83c5384da033c415db155b53fb5b8eba7ff5a039d639")
+	// TestSecretKeyCarol is the secret key of TestAddressCarol
+	TestSecretKeyCarol, _ = hex.DecodeString("e253a571ca153dc2aee845819f74bcc9773b0586edead15a94cb7235a5027436")
+)
+
+// SignWithTestSecretKey signs a payload (ed25519), given the secret key of a test wallet
+func SignWithTestSecretKey(secretKey []byte, payload []byte) []byte {
+	return ed25519.Sign(ed25519.NewKeyFromSeed(secretKey), payload)
+}
+
+// GetPublicKeyOfTestSecretKey returns the public key corresponding to the secret key of a test wallet
+func GetPublicKeyOfTestSecretKey(secretKey []byte) []byte {
+	return ed25519.NewKeyFromSeed(secretKey).Public().(ed25519.PublicKey)
+}
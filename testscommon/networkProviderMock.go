@@ -10,57 +10,72 @@ import (
 	"github.com/ElrondNetwork/elrond-go/sharding"
 	"github.com/ElrondNetwork/elrond-proxy-go/data"
 	"github.com/ElrondNetwork/rosetta/server/resources"
+	"github.com/ElrondNetwork/rosetta/server/signing"
 )
 
 const emptyHash = "0000000000000000000000000000000000000000000000000000000000000000"
 const genesisTimestamp = int64(1596117600)
 
 type networkProviderMock struct {
-	pubKeyConverter core.PubkeyConverter
+	pubKeyConverter        core.PubkeyConverter
+	signingPayloadComputer *signing.TransactionSigningPayloadComputer
 
 	MockIsOffline                   bool
 	MockNumShards                   uint32
 	MockObservedActualShard         uint32
 	MockObservedProjectedShard      uint32
 	MockObservedProjectedShardIsSet bool
-	MockObserverPubkey              string
+	MockMaxInlinedTransactions      uint64
+	MockHistoricalBalanceLookup     bool
+	MockGasLimitSafetyMargin        uint64
 	MockNativeCurrencySymbol        string
+	MockCustomCurrencies            []resources.CustomCurrency
+	MockTrackedContracts            map[string]struct{}
 	MockGenesisBlockHash            string
 	MockGenesisTimestamp            int64
 	MockNetworkConfig               *resources.NetworkConfig
 	MockGenesisBalances             []*resources.GenesisBalance
 	MockLatestBlockSummary          *resources.BlockSummary
+	MockNodeStatus                  *resources.NodeStatus
+	MockPeers                       []data.PubKeyHeartbeat
 	MockBlocksByNonce               map[uint64]*data.Block
 	MockBlocksByHash                map[string]*data.Block
 	MockAccountsByAddress           map[string]*data.Account
+	MockAccountsESDTBalances        map[string]map[string]string
 	MockMempoolTransactionsByHash   map[string]*data.FullTransaction
 	MockComputedTransactionHash     string
 	MockComputedReceiptHash         string
 	MockNextError                   error
 
-	SendTransactionCalled func(tx *data.Transaction) (string, error)
+	SendTransactionCalled         func(tx *data.Transaction) (string, error)
+	SimulateTransactionCostCalled func(tx *data.Transaction) (*data.TxCostResponseData, error)
+	GetAccountESDTBalanceCalled   func(address string, tokenIdentifier string, options resources.AccountQueryOptions) (*resources.AccountESDTBalance, error)
 }
 
 // NewNetworkProviderMock -
 func NewNetworkProviderMock() *networkProviderMock {
 	pubKeyConverter, _ := pubkeyConverter.NewBech32PubkeyConverter(32, log)
+	signingPayloadComputer, _ := signing.NewTransactionSigningPayloadComputer(pubKeyConverter)
 
 	return &networkProviderMock{
 		pubKeyConverter:                 pubKeyConverter,
+		signingPayloadComputer:          signingPayloadComputer,
 		MockIsOffline:                   false,
 		MockNumShards:                   3,
 		MockObservedActualShard:         0,
 		MockObservedProjectedShard:      0,
 		MockObservedProjectedShardIsSet: false,
-		MockObserverPubkey:              "observer",
 		MockNativeCurrencySymbol:        "XeGLD",
+		MockCustomCurrencies:            make([]resources.CustomCurrency, 0),
+		MockTrackedContracts:            make(map[string]struct{}),
 		MockGenesisBlockHash:            emptyHash,
 		MockGenesisTimestamp:            genesisTimestamp,
 		MockNetworkConfig: &resources.NetworkConfig{
-			ChainID:        "test",
-			GasPerDataByte: 1500,
-			MinGasPrice:    1000000000,
-			MinGasLimit:    50000,
+			ChainID:          "test",
+			GasPerDataByte:   1500,
+			MinGasPrice:      1000000000,
+			MinGasLimit:      50000,
+			GasPriceModifier: 0.01,
 		},
 		MockGenesisBalances: make([]*resources.GenesisBalance, 0),
 		MockLatestBlockSummary: &resources.BlockSummary{
@@ -69,9 +84,17 @@ func NewNetworkProviderMock() *networkProviderMock {
 			PreviousBlockHash: emptyHash,
 			Timestamp:         genesisTimestamp,
 		},
+		MockNodeStatus: &resources.NodeStatus{
+			HighestNonce:         0,
+			HighestFinalNonce:    0,
+			ProbableHighestNonce: 0,
+			IsSyncing:            0,
+			PublicKeyBlockSign:   "observer",
+		},
 		MockBlocksByNonce:             make(map[uint64]*data.Block),
 		MockBlocksByHash:              make(map[string]*data.Block),
 		MockAccountsByAddress:         make(map[string]*data.Account),
+		MockAccountsESDTBalances:      make(map[string]map[string]string),
 		MockMempoolTransactionsByHash: make(map[string]*data.FullTransaction),
 		MockComputedTransactionHash:   emptyHash,
 		MockNextError:                 nil,
@@ -101,9 +124,46 @@ func (mock *networkProviderMock) GetNativeCurrency() resources.NativeCurrency {
 	}
 }
 
-// GetObserverPubkey -
-func (mock *networkProviderMock) GetObserverPubkey() string {
-	return mock.MockObserverPubkey
+// GetCustomCurrencies -
+func (mock *networkProviderMock) GetCustomCurrencies() []resources.CustomCurrency {
+	return mock.MockCustomCurrencies
+}
+
+// GetCustomCurrencyBySymbol -
+func (mock *networkProviderMock) GetCustomCurrencyBySymbol(symbol string) (resources.CustomCurrency, bool) {
+	for _, currency := range mock.MockCustomCurrencies {
+		if currency.Symbol == symbol {
+			return currency, true
+		}
+	}
+
+	return resources.CustomCurrency{}, false
+}
+
+// GetCustomCurrencyByIdentifier -
+func (mock *networkProviderMock) GetCustomCurrencyByIdentifier(identifier string) (resources.CustomCurrency, bool) {
+	for _, currency := range mock.MockCustomCurrencies {
+		if currency.Identifier == identifier {
+			return currency, true
+		}
+	}
+
+	return resources.CustomCurrency{}, false
+}
+
+// GetGasLimitSafetyMargin -
+func (mock *networkProviderMock) GetGasLimitSafetyMargin() uint64 {
+	return mock.MockGasLimitSafetyMargin
+}
+
+// IsHistoricalBalanceLookupEnabled -
+func (mock *networkProviderMock) IsHistoricalBalanceLookupEnabled() bool {
+	return mock.MockHistoricalBalanceLookup
+}
+
+// GetMaxInlinedTransactions -
+func (mock *networkProviderMock) GetMaxInlinedTransactions() uint64 {
+	return mock.MockMaxInlinedTransactions
 }
 
 // GetNetworkConfig -
@@ -130,6 +190,27 @@ func (mock *networkProviderMock) GetGenesisBalances() ([]*resources.GenesisBalan
 	return mock.MockGenesisBalances, mock.MockNextError
 }
 
+// GetNodeStatusAndLatestBlockSummary -
+func (mock *networkProviderMock) GetNodeStatusAndLatestBlockSummary() (*resources.NodeStatus, *resources.BlockSummary, error) {
+	if mock.MockNextError != nil {
+		return nil, nil, mock.MockNextError
+	}
+
+	return mock.MockNodeStatus, mock.MockLatestBlockSummary, nil
+}
+
+// GetPeers -
+func (mock *networkProviderMock) GetPeers() ([]data.PubKeyHeartbeat, error) {
+	if mock.MockNextError != nil {
+		return nil, mock.MockNextError
+	}
+	if mock.MockPeers == nil {
+		return nil, fmt.Errorf("peers not available")
+	}
+
+	return mock.MockPeers, nil
+}
+
 // GetLatestBlockSummary -
 func (mock *networkProviderMock) GetLatestBlockSummary() (*resources.BlockSummary, error) {
 	return mock.MockLatestBlockSummary, mock.MockNextError
@@ -156,22 +237,121 @@ func (mock *networkProviderMock) GetBlockByHash(hash string) (*data.Block, error
 }
 
 // GetAccount -
-func (mock *networkProviderMock) GetAccount(address string) (*data.AccountModel, error) {
+func (mock *networkProviderMock) GetAccount(address string, options resources.AccountQueryOptions) (*data.AccountModel, error) {
 	account, ok := mock.MockAccountsByAddress[address]
 	if ok {
 		return &data.AccountModel{
-			Account: *account,
-			BlockInfo: data.BlockInfo{
-				Nonce:    mock.MockLatestBlockSummary.Nonce,
-				Hash:     mock.MockLatestBlockSummary.Hash,
-				RootHash: emptyHash,
-			},
+			Account:   *account,
+			BlockInfo: mock.getBlockInfoGivenQueryOptions(options),
 		}, mock.MockNextError
 	}
 
 	return nil, fmt.Errorf("account %s not found", address)
 }
 
+// GetAccountESDTBalance -
+func (mock *networkProviderMock) GetAccountESDTBalance(address string, tokenIdentifier string, options resources.AccountQueryOptions) (*resources.AccountESDTBalance, error) {
+	if mock.GetAccountESDTBalanceCalled != nil {
+		return mock.GetAccountESDTBalanceCalled(address, tokenIdentifier, options)
+	}
+
+	_, ok := mock.MockAccountsByAddress[address]
+	if !ok {
+		return nil, fmt.Errorf("account %s not found", address)
+	}
+
+	balance, ok := mock.MockAccountsESDTBalances[address][tokenIdentifier]
+	if !ok {
+		balance = "0"
+	}
+
+	return &resources.AccountESDTBalance{
+		Balance:   balance,
+		BlockInfo: mock.getBlockInfoGivenQueryOptions(options),
+	}, mock.MockNextError
+}
+
+// GetAccountAllESDTBalances -
+func (mock *networkProviderMock) GetAccountAllESDTBalances(address string, options resources.AccountQueryOptions) (map[string]*resources.AccountESDTBalance, error) {
+	_, ok := mock.MockAccountsByAddress[address]
+	if !ok {
+		return nil, fmt.Errorf("account %s not found", address)
+	}
+
+	balances := make(map[string]*resources.AccountESDTBalance)
+
+	for tokenIdentifier, balance := range mock.MockAccountsESDTBalances[address] {
+		balances[tokenIdentifier] = &resources.AccountESDTBalance{
+			Balance:   balance,
+			BlockInfo: mock.getBlockInfoGivenQueryOptions(options),
+		}
+	}
+
+	return balances, mock.MockNextError
+}
+
+func (mock *networkProviderMock) getBlockInfoGivenQueryOptions(options resources.AccountQueryOptions) data.BlockInfo {
+	if options.BlockNonce.HasValue {
+		return data.BlockInfo{
+			Nonce:    options.BlockNonce.Value,
+			Hash:     mock.getBlockHashByNonce(options.BlockNonce.Value),
+			RootHash: emptyHash,
+		}
+	}
+
+	if len(options.BlockHash) > 0 {
+		return data.BlockInfo{
+			Nonce:    mock.getBlockNonceByHash(options.BlockHash),
+			Hash:     options.BlockHash,
+			RootHash: emptyHash,
+		}
+	}
+
+	return data.BlockInfo{
+		Nonce:    mock.MockLatestBlockSummary.Nonce,
+		Hash:     mock.MockLatestBlockSummary.Hash,
+		RootHash: emptyHash,
+	}
+}
+
+func (mock *networkProviderMock) getBlockHashByNonce(nonce uint64) string {
+	block, ok := mock.MockBlocksByNonce[nonce]
+	if ok {
+		return block.Hash
+	}
+
+	return emptyHash
+}
+
+func (mock *networkProviderMock) getBlockNonceByHash(hash string) uint64 {
+	block, ok := mock.MockBlocksByHash[hash]
+	if ok {
+		return block.Nonce
+	}
+
+	return 0
+}
+
+// GetObservedActualShard -
+func (mock *networkProviderMock) GetObservedActualShard() uint32 {
+	return mock.MockObservedActualShard
+}
+
+// ComputeShardIdOfAddress -
+func (mock *networkProviderMock) ComputeShardIdOfAddress(address string) (uint32, error) {
+	shardCoordinator, err := sharding.NewMultiShardCoordinator(mock.MockNumShards, mock.MockObservedActualShard)
+	if err != nil {
+		return 0, err
+	}
+
+	pubKey, err := mock.ConvertAddressToPubKey(address)
+	if err != nil {
+		return 0, err
+	}
+
+	return shardCoordinator.ComputeId(pubKey), nil
+}
+
 // IsAddressObserved -
 func (mock *networkProviderMock) IsAddressObserved(address string) (bool, error) {
 	shardCoordinator, err := sharding.NewMultiShardCoordinator(mock.MockNumShards, mock.MockObservedActualShard)
@@ -196,6 +376,12 @@ func (mock *networkProviderMock) IsAddressObserved(address string) (bool, error)
 	return isObservedActualShard, nil
 }
 
+// IsContractAddressTracked -
+func (mock *networkProviderMock) IsContractAddressTracked(address string) bool {
+	_, ok := mock.MockTrackedContracts[address]
+	return ok
+}
+
 // ConvertPubKeyToAddress -
 func (mock *networkProviderMock) ConvertPubKeyToAddress(pubkey []byte) string {
 	return mock.pubKeyConverter.Encode(pubkey)
@@ -211,11 +397,38 @@ func (mock *networkProviderMock) ComputeTransactionHash(tx *data.Transaction) (s
 	return mock.MockComputedTransactionHash, mock.MockNextError
 }
 
+// ComputeTransactionSigningPayload -
+func (mock *networkProviderMock) ComputeTransactionSigningPayload(tx *data.Transaction) ([]byte, error) {
+	return mock.signingPayloadComputer.ComputeSigningPayload(tx)
+}
+
 // ComputeReceiptHash -
 func (mock *networkProviderMock) ComputeReceiptHash(apiReceipt *transaction.ApiReceipt) (string, error) {
 	return mock.MockComputedReceiptHash, mock.MockNextError
 }
 
+// GetMempoolTransactions -
+func (mock *networkProviderMock) GetMempoolTransactions() ([]*resources.TransactionInPool, error) {
+	if mock.MockNextError != nil {
+		return nil, mock.MockNextError
+	}
+
+	txs := make([]*resources.TransactionInPool, 0, len(mock.MockMempoolTransactionsByHash))
+	for _, tx := range mock.MockMempoolTransactionsByHash {
+		txs = append(txs, &resources.TransactionInPool{
+			Hash:     tx.Hash,
+			Sender:   tx.Sender,
+			Receiver: tx.Receiver,
+			Value:    tx.Value,
+			Data:     tx.Data,
+			GasLimit: tx.GasLimit,
+			GasPrice: tx.GasPrice,
+		})
+	}
+
+	return txs, nil
+}
+
 // ComputeTransactionFeeForMoveBalance -
 func (mock *networkProviderMock) ComputeTransactionFeeForMoveBalance(tx *data.FullTransaction) *big.Int {
 	minGasLimit := mock.MockNetworkConfig.MinGasLimit
@@ -226,6 +439,22 @@ func (mock *networkProviderMock) ComputeTransactionFeeForMoveBalance(tx *data.Fu
 	return fee
 }
 
+// ComputeTransactionFee -
+func (mock *networkProviderMock) ComputeTransactionFee(tx *data.FullTransaction) *big.Int {
+	minGasLimit := mock.MockNetworkConfig.MinGasLimit
+	gasPerDataByte := mock.MockNetworkConfig.GasPerDataByte
+	gasLimitForMoveBalance := minGasLimit + gasPerDataByte*uint64(len(tx.Data))
+	feeForMoveBalance := core.SafeMul(gasLimitForMoveBalance, tx.GasPrice)
+
+	if tx.GasLimit <= gasLimitForMoveBalance {
+		return feeForMoveBalance
+	}
+
+	gasPriceForProcessing := uint64(mock.MockNetworkConfig.GasPriceModifier * float64(tx.GasPrice))
+	feeForProcessing := core.SafeMul(tx.GasLimit-gasLimitForMoveBalance, gasPriceForProcessing)
+	return big.NewInt(0).Add(feeForMoveBalance, feeForProcessing)
+}
+
 // SendTransaction -
 func (mock *networkProviderMock) SendTransaction(tx *data.Transaction) (string, error) {
 	if mock.SendTransactionCalled != nil {
@@ -235,6 +464,15 @@ func (mock *networkProviderMock) SendTransaction(tx *data.Transaction) (string,
 	return mock.MockComputedTransactionHash, mock.MockNextError
 }
 
+// SimulateTransactionCost -
+func (mock *networkProviderMock) SimulateTransactionCost(tx *data.Transaction) (*data.TxCostResponseData, error) {
+	if mock.SimulateTransactionCostCalled != nil {
+		return mock.SimulateTransactionCostCalled(tx)
+	}
+
+	return &data.TxCostResponseData{}, mock.MockNextError
+}
+
 // GetMempoolTransactionByHash -
 func (mock *networkProviderMock) GetMempoolTransactionByHash(hash string) (*data.FullTransaction, error) {
 	transaction, ok := mock.MockMempoolTransactionsByHash[hash]